@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MHmorgan/reminders/git"
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+)
+
+// blamer finds the commits which introduced reminders.
+type blamer struct {
+	repo  *git.Repo
+	start git.Hash
+	// Whether the scanned files are read from the working tree,
+	// rather than from the start commit
+	worktree bool
+}
+
+// Returns a blamer for the reminders of the given revision, or of
+// the working tree if rev is empty.
+func newBlamer(rev string) (*blamer, error) {
+	repo, err := git.Find(".")
+	if err != nil {
+		return nil, err
+	}
+	b := &blamer{repo: repo, worktree: rev == ""}
+	if rev == "" {
+		rev = "HEAD"
+	}
+	if b.start, err = repo.Resolve(rev); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Set the origin of the reminders of a file, which are not committed
+// if they have no origin afterwards.
+func (b *blamer) blame(path string, reminders []reminder.Reminder) error {
+	rel, err := b.repo.Rel(path)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if b.worktree {
+		if data, err = os.ReadFile(path); err != nil {
+			return err
+		}
+	}
+
+	lines := make([]int, len(reminders))
+	for i, r := range reminders {
+		lines[i] = r.Line()
+	}
+	commits, err := b.repo.Blame(b.start, rel, lines, data)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range reminders {
+		if c := commits[r.Line()]; c != nil {
+			reminders[i].SetOrigin(reminder.Origin{
+				Commit: c.Hash.String(),
+				Author: c.Author.Name,
+				Email:  c.Author.Email,
+				Date:   c.Author.When,
+			})
+		}
+	}
+	return nil
+}
+
+// Pass on the scan results with the origin of each reminder set,
+// blaming the files in parallel.
+func (b *blamer) enrich(in <-chan scanner.Result) <-chan scanner.Result {
+	n := nWorkers()
+	out := make(chan scanner.Result, n)
+	sem := make(chan struct{}, n)
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(out)
+		}()
+
+		for res := range in {
+			reminders := make(chan reminder.Reminder, 1)
+			out <- scanner.Result{Path: res.Path, Reminders: reminders}
+
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(reminders)
+
+				var rs []reminder.Reminder
+				for r := range res.Reminders {
+					rs = append(rs, r)
+				}
+				if len(rs) > 0 {
+					if err := b.blame(res.Path, rs); err != nil {
+						fmt.Fprintf(os.Stderr, "Failed to blame %q: %v\n", res.Path, err)
+					}
+				}
+				<-sem
+
+				for _, r := range rs {
+					reminders <- r
+				}
+			}()
+		}
+	}()
+
+	return out
+}
+
+// Parse an age like 90d, 2w, 1y or 36h.
+func parseAge(s string) (time.Duration, error) {
+	const day = 24 * time.Hour
+	units := map[string]time.Duration{"d": day, "w": 7 * day, "y": 365 * day}
+	for suffix, unit := range units {
+		if num, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(num)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MHmorgan/reminders/git"
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+)
+
+// changes holds the files of the commit which the changes in the
+// working tree are compared against.
+type changes struct {
+	repo *git.Repo
+	base map[string]git.TreeEntry
+}
+
+// Load the files of the merge base of HEAD and the given revision,
+// which is the base of the current branch when the revision is the
+// branch it was created from.
+func loadChanges(rev string) (*changes, error) {
+	repo, err := git.Find(".")
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Resolve("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	since, err := repo.Resolve(rev)
+	if err != nil {
+		return nil, err
+	}
+	base, err := repo.MergeBase(since, head)
+	if err != nil {
+		return nil, err
+	}
+	c, err := repo.Commit(base)
+	if err != nil {
+		return nil, err
+	}
+	files, err := repo.TreeFiles(c.Tree)
+	if err != nil {
+		return nil, err
+	}
+	return &changes{repo, files}, nil
+}
+
+// Returns the files among the given files and directories which may
+// have lines added since the base commit: the tracked files which
+// differ from the base commit in the index, or are modified in the
+// working tree. Untracked files are not part of the branch until
+// they're added. Paths are joined with the file or directory they're
+// found in, like those of a search.
+func (c *changes) paths(roots []string) ([]string, error) {
+	idx, err := c.repo.Index()
+	if err != nil {
+		return nil, err
+	}
+	absRoots := make([]string, len(roots))
+	for i, root := range roots {
+		if absRoots[i], err = filepath.Abs(root); err != nil {
+			return nil, err
+		}
+	}
+
+	var paths []string
+	for _, e := range idx.Entries {
+		if e.SkipWorktree {
+			continue
+		}
+		file := filepath.Join(c.repo.WorkTree, filepath.FromSlash(e.Path))
+		if base, ok := c.base[e.Path]; ok && e.Stage == 0 && base.Hash == e.Hash && !idx.Stale(e, file) {
+			continue
+		}
+		for i, root := range absRoots {
+			rel, err := filepath.Rel(root, file)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				paths = append(paths, filepath.Join(roots[i], rel))
+				break
+			}
+		}
+	}
+	return paths, nil
+}
+
+// Returns the lines of the file added since the base commit.
+func (c *changes) addedLines(path string) ([]git.LineRange, error) {
+	rel, err := c.repo.Rel(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := c.base[rel]
+	if !ok {
+		return git.AddedLines(nil, data), nil
+	}
+	if git.HashObject(git.BlobObject, data) == entry.Hash {
+		return nil, nil
+	}
+	obj, err := c.repo.Object(entry.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return git.AddedLines(obj.Data, data), nil
+}
+
+// Pass on the scan results with only the reminders which are on
+// lines added since the base commit.
+func (c *changes) filter(in <-chan scanner.Result) <-chan scanner.Result {
+	out := make(chan scanner.Result, 1)
+
+	go func() {
+		defer close(out)
+
+		for res := range in {
+			reminders := make(chan reminder.Reminder, 1)
+			out <- scanner.Result{Path: res.Path, Reminders: reminders}
+
+			added, err := c.addedLines(res.Path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to diff %q: %v\n", res.Path, err)
+			}
+			for r := range res.Reminders {
+				if isAdded(r, added) {
+					reminders <- r
+				}
+			}
+			close(reminders)
+		}
+	}()
+
+	return out
+}
+
+// Reports whether any line of the reminder is added.
+func isAdded(r reminder.Reminder, added []git.LineRange) bool {
+	for _, lr := range added {
+		if lr.Start <= r.EndLine() && r.Line() <= lr.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+	"github.com/MHmorgan/reminders/searcher"
+)
+
+// The name of the project configuration file, read from the
+// current directory.
+const projectConfig = ".reminders.json"
+
+// config is the user configuration, read from JSON files.
+type config struct {
+	// Additional languages, replacing the built-in ones
+	// for the same extensions.
+	Languages []scanner.Language `json:"languages"`
+
+	// Keywords recognized in keyword mode, in addition to the
+	// default ones. An empty tag disables a default keyword.
+	Keywords map[string]string `json:"keywords"`
+
+	// Tag aliases, in addition to the default ones. An empty
+	// canonical tag disables a default alias.
+	Aliases map[string]string `json:"aliases"`
+
+	// Include and exclude rules for the searched files, applied
+	// before the rules given by flags.
+	Files []searcher.Rule `json:"files"`
+
+	// SARIF severity levels by tag, in addition to the default
+	// ones: error, warning, note or none.
+	Severities map[string]string `json:"severities"`
+
+	// The link template of reports, like
+	// https://host/repo/blob/main/{path}#L{line}.
+	ReportURL string `json:"report_url"`
+}
+
+// severities maps tags to SARIF severity levels. Tags without a level
+// have the level of their closest parent in the tag hierarchy, or note.
+type severities map[string]string
+
+// The default severity levels, for tags which aren't configured.
+var defaultSeverities = severities{
+	"bug": "error",
+	"fix": "warning",
+}
+
+// Return the severity level of the tag.
+func (s severities) level(tag string) string {
+	for {
+		if l, ok := s[tag]; ok {
+			return l
+		}
+		i := strings.LastIndexByte(tag, '/')
+		if i < 0 {
+			return "note"
+		}
+		tag = tag[:i]
+	}
+}
+
+// Load the configuration files which exist among the default ones:
+// the user configuration followed by the project configuration.
+// If path is non-empty it replaces the project configuration,
+// and must exist.
+func loadConfig(path string) (config, error) {
+	var paths []string
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "reminders", "config.json"))
+	}
+	if path == "" {
+		paths = append(paths, projectConfig)
+	}
+
+	var cfg config
+	for _, p := range paths {
+		c, err := readConfig(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return cfg, err
+		}
+		cfg.merge(c)
+	}
+
+	if path != "" {
+		c, err := readConfig(path)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.merge(c)
+	}
+	return cfg, nil
+}
+
+func readConfig(path string) (config, error) {
+	var cfg config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Merge another configuration into this one, with the other
+// configuration taking precedence.
+func (c *config) merge(other config) {
+	c.Languages = append(c.Languages, other.Languages...)
+	c.Keywords = mergeMap(c.Keywords, other.Keywords)
+	c.Aliases = mergeMap(c.Aliases, other.Aliases)
+	c.Files = append(c.Files, other.Files...)
+	c.Severities = mergeMap(c.Severities, other.Severities)
+	c.ReportURL = cmp.Or(other.ReportURL, c.ReportURL)
+}
+
+// Returns the keywords of keyword mode, merging the configured
+// keywords into the default ones. Keywords must be upper-case
+// letters, like TODO, which is all the scanner recognizes.
+func (c *config) keywords() (map[string]string, error) {
+	kw := mergeMap(nil, scanner.DefaultKeywords)
+	for word, tag := range c.Keywords {
+		if word == "" || strings.TrimFunc(word, func(r rune) bool { return 'A' <= r && r <= 'Z' }) != "" {
+			return nil, fmt.Errorf("invalid keyword %q: keywords must be upper-case letters", word)
+		}
+		if tag == "" {
+			delete(kw, word)
+		} else {
+			kw[word] = reminder.NormalizeTag(tag)
+		}
+	}
+	return kw, nil
+}
+
+// Returns the tag aliases, merging the configured aliases into
+// the default ones.
+func (c *config) aliases() reminder.Aliases {
+	aliases := mergeMap(nil, scanner.DefaultAliases)
+	for tag, canon := range c.Aliases {
+		tag = reminder.NormalizeTag(tag)
+		if canon == "" {
+			delete(aliases, tag)
+		} else {
+			aliases[tag] = reminder.NormalizeTag(canon)
+		}
+	}
+	return aliases
+}
+
+// Returns the SARIF severity levels, merging the configured levels
+// into the default ones.
+func (c *config) severities() (severities, error) {
+	levels := mergeMap(nil, defaultSeverities)
+	for tag, level := range c.Severities {
+		switch level {
+		case "error", "warning", "note", "none":
+		default:
+			return nil, fmt.Errorf("invalid severity %q of tag %q", level, tag)
+		}
+		levels[reminder.NormalizeTag(tag)] = level
+	}
+	return levels, nil
+}
+
+func mergeMap[M ~map[K]V, K comparable, V any](dst, src M) M {
+	if dst == nil && src != nil {
+		dst = make(M, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
+
+// Apply the configuration to the application packages.
+func (c *config) apply() error {
+	for _, lang := range c.Languages {
+		if err := scanner.Register(lang); err != nil {
+			return err
+		}
+	}
+	return nil
+}
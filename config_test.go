@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestKeywords(t *testing.T) {
+	cfg := config{Keywords: map[string]string{"NOTE": "Todo", "PERF": "Fix.Perf", "HACK": ""}}
+	kw, err := cfg.keywords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kw["NOTE"] != "todo" {
+		t.Errorf("expected NOTE to be the todo tag, got %q", kw["NOTE"])
+	}
+	if kw["PERF"] != "fix/perf" {
+		t.Errorf("expected PERF to be the fix/perf tag, got %q", kw["PERF"])
+	}
+	if _, ok := kw["HACK"]; ok {
+		t.Error("expected HACK to be disabled")
+	}
+	if kw["TODO"] != "todo" {
+		t.Errorf("expected the default TODO keyword, got %q", kw["TODO"])
+	}
+
+	for _, word := range []string{"Note", "TODO2", "TO-DO", ""} {
+		cfg := config{Keywords: map[string]string{word: "todo"}}
+		if _, err := cfg.keywords(); err == nil {
+			t.Errorf("expected an error for keyword %q", word)
+		}
+	}
+}
+
+func TestSeverities(t *testing.T) {
+	cfg := config{Severities: map[string]string{"Later": "none", "bug/docs": "note", "chore": "warning"}}
+	levels, err := cfg.severities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]string{
+		"bug":       "error",
+		"bug/ui":    "error",
+		"bug/docs":  "note",
+		"fix":       "warning",
+		"later":     "none",
+		"chore/ci":  "warning",
+		"todo":      "note",
+		"something": "note",
+	}
+	for tag, level := range expect {
+		if got := levels.level(tag); got != level {
+			t.Errorf("%s: expected level %s, got %s", tag, level, got)
+		}
+	}
+
+	cfg = config{Severities: map[string]string{"bug": "fatal"}}
+	if _, err := cfg.severities(); err == nil {
+		t.Error("expected an error for an invalid level")
+	}
+}
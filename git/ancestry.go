@@ -0,0 +1,90 @@
+package git
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// MergeBase returns a best common ancestor of two commits, like the
+// base of a branch.
+func (r *Repo) MergeBase(a, b Hash) (Hash, error) {
+	const (
+		fromA = 1 << iota
+		fromB
+	)
+
+	// Walk the ancestors of both commits, newest first, until
+	// reaching a commit which is an ancestor of both
+	flags := make(map[Hash]int)
+	var q commitQueue
+	for _, start := range []struct {
+		h    Hash
+		flag int
+	}{{a, fromA}, {b, fromB}} {
+		c, err := r.Commit(start.h)
+		if err != nil {
+			return Hash{}, err
+		}
+		flags[c.Hash] |= start.flag
+		heap.Push(&q, c)
+	}
+
+	for q.Len() > 0 {
+		c := heap.Pop(&q).(*Commit)
+		f := flags[c.Hash]
+		if f == fromA|fromB {
+			return c.Hash, nil
+		}
+		for _, p := range c.Parents {
+			if flags[p]&f == f {
+				continue
+			}
+			pc, err := r.Commit(p)
+			if err != nil {
+				return Hash{}, err
+			}
+			flags[p] |= f
+			heap.Push(&q, pc)
+		}
+	}
+	return Hash{}, fmt.Errorf("%w: no common ancestor of %s and %s", ErrNotFound, a, b)
+}
+
+// commitQueue orders commits by committer date, newest first.
+type commitQueue []*Commit
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)   { *q = append(*q, x.(*Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// FirstParents returns the commits following the first parents from
+// a commit, newest first, until reaching the stop commit, which is
+// left out, or the root commit.
+func (r *Repo) FirstParents(from, stop Hash) ([]*Commit, error) {
+	var commits []*Commit
+	h := from
+	for h != stop {
+		c, err := r.Commit(h)
+		if err != nil {
+			return nil, err
+		}
+		if c.Hash == stop {
+			break
+		}
+		commits = append(commits, c)
+		if len(c.Parents) == 0 {
+			break
+		}
+		h = c.Parents[0]
+	}
+	return commits, nil
+}
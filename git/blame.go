@@ -0,0 +1,168 @@
+package git
+
+import (
+	"container/heap"
+	"errors"
+	"strings"
+)
+
+// blameState is the lines of a file being blamed at a commit, by their
+// line number at the commit, mapped to the blamed lines.
+type blameState struct {
+	commit *Commit
+	blob   Hash
+	lines  map[int][]int
+}
+
+// Blame returns the commits which introduced the given 1-based lines
+// of a file at the start commit, by line. Lines are followed through
+// the history of the file path, without detecting renames.
+//
+// If worktree is not nil, it is the content of the file in the working
+// tree, which the lines refer to, and lines which are not committed
+// are left out.
+func (r *Repo) Blame(start Hash, path string, lines []int, worktree []byte) (map[int]*Commit, error) {
+	blamed := make(map[int]*Commit, len(lines))
+
+	c, err := r.Commit(start)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := r.TreeEntry(c.Tree, path)
+	if errors.Is(err, ErrNotFound) {
+		// A new file
+		return blamed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st := &blameState{c, entry.Hash, make(map[int][]int)}
+	if worktree != nil {
+		obj, err := r.Object(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		matches := MatchLines(obj.Data, worktree)
+		for _, l := range lines {
+			if l > 0 && l <= len(matches) && matches[l-1] != 0 {
+				st.lines[matches[l-1]] = append(st.lines[matches[l-1]], l)
+			}
+		}
+	} else {
+		for _, l := range lines {
+			st.lines[l] = append(st.lines[l], l)
+		}
+	}
+
+	pending := map[Hash]*blameState{c.Hash: st}
+	q := commitQueue{c}
+	for q.Len() > 0 {
+		c := heap.Pop(&q).(*Commit)
+		st := pending[c.Hash]
+		delete(pending, c.Hash)
+
+		// Pass the lines which are unchanged in a parent on to it,
+		// and blame the rest on the commit
+		rest := st.lines
+		for _, ph := range c.Parents {
+			if len(rest) == 0 {
+				break
+			}
+			pc, err := r.Commit(ph)
+			if errors.Is(err, ErrNotFound) {
+				// Missing in a shallow clone
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			entry, err := r.TreeEntry(pc.Tree, path)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			passed := rest
+			if entry.Hash == st.blob {
+				rest = nil
+			} else {
+				if passed, err = r.passLines(entry.Hash, st.blob, rest); err != nil {
+					return nil, err
+				}
+			}
+			if len(passed) == 0 {
+				continue
+			}
+
+			pst, ok := pending[ph]
+			if !ok {
+				pst = &blameState{pc, entry.Hash, make(map[int][]int)}
+				pending[ph] = pst
+				heap.Push(&q, pc)
+			}
+			for l, blamedLines := range passed {
+				pst.lines[l] = append(pst.lines[l], blamedLines...)
+			}
+		}
+
+		for _, blamedLines := range rest {
+			for _, l := range blamedLines {
+				blamed[l] = c
+			}
+		}
+	}
+	return blamed, nil
+}
+
+// passLines returns the lines of the blob which are unchanged in the
+// parent blob, by their line number in the parent, removing them from
+// lines.
+func (r *Repo) passLines(parent, blob Hash, lines map[int][]int) (map[int][]int, error) {
+	old, err := r.Object(parent)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := r.Object(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	passed := make(map[int][]int)
+	matches := MatchLines(old.Data, cur.Data)
+	for l, blamedLines := range lines {
+		if l <= len(matches) && matches[l-1] != 0 {
+			passed[matches[l-1]] = blamedLines
+			delete(lines, l)
+		}
+	}
+	return passed, nil
+}
+
+// TreeEntry returns the entry of a slash-separated path in a tree,
+// or in the tree of a commit.
+func (r *Repo) TreeEntry(tree Hash, path string) (TreeEntry, error) {
+	e := TreeEntry{Mode: ModeDir, Hash: tree}
+	for name := range strings.SplitSeq(path, "/") {
+		if e.Mode != ModeDir {
+			return TreeEntry{}, ErrNotFound
+		}
+		entries, err := r.Tree(e.Hash)
+		if err != nil {
+			return TreeEntry{}, err
+		}
+		found := false
+		for _, te := range entries {
+			if te.Name == name {
+				e, found = te, true
+				break
+			}
+		}
+		if !found {
+			return TreeEntry{}, ErrNotFound
+		}
+	}
+	return e, nil
+}
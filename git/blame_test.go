@@ -0,0 +1,61 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBlame(t *testing.T) {
+	dir := historyRepo(t)
+	runGit(t, dir, "checkout", "-q", "main")
+	runGit(t, dir, "merge", "-q", "--no-ff", "-m", "Merge feature", "feature")
+	commitFiles(t, dir, "Fourth", map[string]string{"a.go": "// @fix Fourth\npackage a\n\n// @todo Third\n"})
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+	head := mustResolve(t, repo, "HEAD")
+
+	for _, file := range []string{"a.go", "big.txt", "b/c.go"} {
+		var lines []int
+		var expect []string
+		for i, line := range strings.Split(strings.TrimSpace(runGit(t, dir, "blame", "-l", "-s", "HEAD", "--", file)), "\n") {
+			lines = append(lines, i+1)
+			expect = append(expect, strings.TrimPrefix(strings.Fields(line)[0], "^"))
+		}
+
+		blamed, err := repo.Blame(head, file, lines, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, l := range lines {
+			// Boundary commits are abbreviated by git
+			if c := blamed[l]; c == nil || !strings.HasPrefix(c.Hash.String(), expect[i]) {
+				t.Errorf("%s:%d: expected %s, got %v", file, l, expect[i], c)
+			}
+		}
+	}
+
+	// Uncommitted lines of the working tree are left out
+	data := []byte("// @fix Fourth\npackage a\n// @later Uncommitted\n\n// @todo Third\n")
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	blamed, err := repo.Blame(head, "a.go", []int{1, 3, 5}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := blamed[1]; c == nil || c.Summary() != "Fourth" {
+		t.Errorf("line 1: expected Fourth, got %v", c)
+	}
+	if c := blamed[5]; c == nil || c.Summary() != "Third" {
+		t.Errorf("line 5: expected Third, got %v", c)
+	}
+	if c, ok := blamed[3]; ok {
+		t.Errorf("line 3: expected no commit, got %v", c)
+	}
+}
@@ -0,0 +1,232 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errBadCommit = errors.New("git: invalid commit")
+
+// Signature is the author or committer of a commit.
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// Commit is a parsed commit object.
+type Commit struct {
+	Hash      Hash
+	Tree      Hash
+	Parents   []Hash
+	Author    Signature
+	Committer Signature
+	Message   string
+}
+
+// Summary returns the first line of the commit message.
+func (c *Commit) Summary() string {
+	line, _, _ := strings.Cut(c.Message, "\n")
+	return line
+}
+
+// Commit reads a commit, peeling annotated tags pointing to it.
+func (r *Repo) Commit(h Hash) (*Commit, error) {
+	obj, err := r.peel(h, CommitObject)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseCommit(obj.Data)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", h, err)
+	}
+	c.Hash = HashObject(CommitObject, obj.Data)
+	return c, nil
+}
+
+// peel reads an object, following annotated tags until an object
+// of the wanted type.
+func (r *Repo) peel(h Hash, want ObjectType) (Object, error) {
+	for range 16 {
+		obj, err := r.Object(h)
+		if err != nil {
+			return obj, err
+		}
+		switch obj.Type {
+		case want:
+			return obj, nil
+		case TagObject:
+			target, ok := header(obj.Data, "object")
+			if !ok {
+				return obj, fmt.Errorf("git: invalid tag %s", h)
+			}
+			if h, err = ParseHash(target); err != nil {
+				return obj, err
+			}
+		default:
+			return obj, fmt.Errorf("git: %s is a %s, not a %s", h, obj.Type, want)
+		}
+	}
+	return Object{}, fmt.Errorf("git: too many nested tags at %s", h)
+}
+
+// header returns the value of the first header with the given key
+// of a commit or tag object.
+func header(data []byte, key string) (string, bool) {
+	for line := range bytes.Lines(data) {
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+		if len(line) == 0 {
+			break
+		}
+		k, v, _ := bytes.Cut(line, []byte{' '})
+		if string(k) == key {
+			return string(v), true
+		}
+	}
+	return "", false
+}
+
+func parseCommit(data []byte) (*Commit, error) {
+	c := &Commit{}
+	headers, msg, _ := bytes.Cut(data, []byte("\n\n"))
+	c.Message = string(msg)
+
+	for line := range bytes.Lines(headers) {
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+		key, val, _ := bytes.Cut(line, []byte{' '})
+		var err error
+		switch string(key) {
+		case "tree":
+			c.Tree, err = ParseHash(string(val))
+		case "parent":
+			var h Hash
+			h, err = ParseHash(string(val))
+			c.Parents = append(c.Parents, h)
+		case "author":
+			c.Author, err = parseSignature(string(val))
+		case "committer":
+			c.Committer, err = parseSignature(string(val))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c.Tree.IsZero() {
+		return nil, errBadCommit
+	}
+	return c, nil
+}
+
+// parseSignature parses a signature like
+// "Alice <alice@example.com> 1700000000 +0100".
+func parseSignature(s string) (Signature, error) {
+	var sig Signature
+	open := strings.LastIndexByte(s, '<')
+	close := strings.LastIndexByte(s, '>')
+	if open < 0 || close < open {
+		return sig, errBadCommit
+	}
+	sig.Name = strings.TrimSpace(s[:open])
+	sig.Email = s[open+1 : close]
+
+	fields := strings.Fields(s[close+1:])
+	if len(fields) != 2 {
+		return sig, errBadCommit
+	}
+	sec, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return sig, errBadCommit
+	}
+	zone, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return sig, errBadCommit
+	}
+	offset := (zone/100*60 + zone%100) * 60
+	sig.When = time.Unix(sec, 0).In(time.FixedZone(fields[1], offset))
+	return sig, nil
+}
+
+// TreeEntry is an entry of a tree object.
+type TreeEntry struct {
+	Name string
+	Mode uint32
+	Hash Hash
+}
+
+// Tree reads the entries of a tree, peeling commits and tags to
+// their trees.
+func (r *Repo) Tree(h Hash) ([]TreeEntry, error) {
+	obj, err := r.Object(h)
+	if err != nil {
+		return nil, err
+	}
+	switch obj.Type {
+	case CommitObject, TagObject:
+		c, err := r.Commit(h)
+		if err != nil {
+			return nil, err
+		}
+		if obj, err = r.Object(c.Tree); err != nil {
+			return nil, err
+		}
+	}
+	if obj.Type != TreeObject {
+		return nil, fmt.Errorf("git: %s is a %s, not a tree", h, obj.Type)
+	}
+	return parseTree(obj.Data)
+}
+
+func parseTree(data []byte) ([]TreeEntry, error) {
+	var entries []TreeEntry
+	for len(data) > 0 {
+		sp := bytes.IndexByte(data, ' ')
+		nul := bytes.IndexByte(data, 0)
+		if sp < 0 || nul < sp || nul+21 > len(data) {
+			return nil, errors.New("git: invalid tree")
+		}
+		mode, err := strconv.ParseUint(string(data[:sp]), 8, 32)
+		if err != nil {
+			return nil, errors.New("git: invalid tree")
+		}
+		e := TreeEntry{
+			Name: string(data[sp+1 : nul]),
+			Mode: uint32(mode),
+			Hash: Hash(data[nul+1 : nul+21]),
+		}
+		entries = append(entries, e)
+		data = data[nul+21:]
+	}
+	return entries, nil
+}
+
+// TreeFiles returns the files of a tree and its subtrees, by their
+// slash-separated paths. Submodules are left out.
+func (r *Repo) TreeFiles(h Hash) (map[string]TreeEntry, error) {
+	files := make(map[string]TreeEntry)
+	var walk func(h Hash, dir string) error
+	walk = func(h Hash, dir string) error {
+		entries, err := r.Tree(h)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			p := path.Join(dir, e.Name)
+			switch e.Mode {
+			case ModeDir:
+				if err := walk(e.Hash, p); err != nil {
+					return err
+				}
+			case ModeGitlink:
+			default:
+				files[p] = e
+			}
+		}
+		return nil
+	}
+	return files, walk(h, ".")
+}
@@ -0,0 +1,162 @@
+package git
+
+import "bytes"
+
+// The largest number of edits computed exactly by AddedLines. Larger
+// changes are reported as one change spanning the differing lines.
+const maxEdits = 4096
+
+// LineRange is an inclusive range of 1-based line numbers.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Contains reports whether the range contains the line.
+func (r LineRange) Contains(line int) bool {
+	return r.Start <= line && line <= r.End
+}
+
+// AddedLines returns the ranges of the lines of b which are added or
+// changed relative to a.
+func AddedLines(a, b []byte) []LineRange {
+	var ranges []LineRange
+	for i, m := range MatchLines(a, b) {
+		line := i + 1
+		if m != 0 {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].End == line-1 {
+			ranges[n-1].End = line
+		} else {
+			ranges = append(ranges, LineRange{line, line})
+		}
+	}
+	return ranges
+}
+
+// MatchLines returns the 1-based number of the line of a matching each
+// line of b, or 0 for added or changed lines, using the Myers diff
+// algorithm.
+func MatchLines(a, b []byte) []int {
+	x, y := splitLines(a, b)
+	matches := make([]int, len(y))
+
+	// Trim the common prefix and suffix
+	pre := 0
+	for pre < len(x) && pre < len(y) && x[pre] == y[pre] {
+		matches[pre] = pre + 1
+		pre++
+	}
+	suf := 0
+	for suf < len(x)-pre && suf < len(y)-pre && x[len(x)-1-suf] == y[len(y)-1-suf] {
+		matches[len(y)-1-suf] = len(x) - suf
+		suf++
+	}
+
+	mid := myers(x[pre:len(x)-suf], y[pre:len(y)-suf])
+	for i, m := range mid {
+		if m != 0 {
+			matches[pre+i] = pre + m
+		}
+	}
+	return matches
+}
+
+// splitLines returns the lines of a and b as numbers, which are
+// equal for equal lines.
+func splitLines(a, b []byte) ([]int, []int) {
+	ids := make(map[string]int)
+	split := func(data []byte) []int {
+		var lines []int
+		for line := range bytes.Lines(data) {
+			line = bytes.TrimSuffix(line, []byte{'\n'})
+			id, ok := ids[string(line)]
+			if !ok {
+				id = len(ids)
+				ids[string(line)] = id
+			}
+			lines = append(lines, id)
+		}
+		return lines
+	}
+	return split(a), split(b)
+}
+
+// myers returns the 1-based index of the element of a matching each
+// element of b in a shortest edit script from a to b, or 0 for inserted
+// elements. All elements are inserted if the script is longer than
+// maxEdits.
+func myers(a, b []int) []int {
+	n, m := len(a), len(b)
+	matches := make([]int, m)
+	if n == 0 || m == 0 {
+		return matches
+	}
+	limit := min(n+m, maxEdits)
+
+	// v[off+k] is the furthest x reached on diagonal k = x-y,
+	// and trace[d] is v after d edits, for k in [-d, d]
+	off := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, v[off-d:off+d+1])
+				backtrack(trace, n, m, matches)
+				return matches
+			}
+		}
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+	}
+	return matches
+}
+
+// backtrack follows the edit script found by myers backwards, from
+// the end of a and b, setting the matching elements.
+func backtrack(trace [][]int, n, m int, matches []int) {
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		// The furthest x of diagonal k after d-1 edits
+		prev := func(k int) int { return trace[d-1][k+d-1] }
+
+		k := x - y
+		var pk int
+		if k == -d || (k != d && prev(k-1) < prev(k+1)) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := prev(pk)
+		py := px - pk
+
+		// The edit, moving down for an insertion or right for
+		// a deletion, is followed by a snake of matches to (x, y)
+		mx, my := px, py+1
+		if pk == k-1 {
+			mx, my = px+1, py
+		}
+		for ; my < y; my, mx = my+1, mx+1 {
+			matches[my] = mx + 1
+		}
+		x, y = px, py
+	}
+	for i := range y {
+		matches[i] = i + 1
+	}
+}
@@ -0,0 +1,29 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+var diffTests = []struct {
+	a, b  string
+	added []LineRange
+}{
+	{"", "", nil},
+	{"a\nb\n", "a\nb\n", nil},
+	{"", "a\nb\n", []LineRange{{1, 2}}},
+	{"a\nb\n", "", nil},
+	{"a\nb\nc\n", "a\nx\nc\n", []LineRange{{2, 2}}},
+	{"a\nb\nc\n", "x\na\nb\nc\ny\n", []LineRange{{1, 1}, {5, 5}}},
+	{"a\nb\nc\nd\n", "a\nc\nb\nd\n", []LineRange{{3, 3}}},
+	{"a\nb\n", "a\nb", nil},
+	{"a\nb\nc\nd\ne\n", "b\nx\ny\nd\ne\nz\n", []LineRange{{2, 3}, {6, 6}}},
+}
+
+func TestAddedLines(t *testing.T) {
+	for _, tt := range diffTests {
+		if got := AddedLines([]byte(tt.a), []byte(tt.b)); !slices.Equal(got, tt.added) {
+			t.Errorf("diff %q -> %q: expected %v, got %v", tt.a, tt.b, tt.added, got)
+		}
+	}
+}
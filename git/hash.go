@@ -0,0 +1,30 @@
+package git
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// Hash is the SHA-1 name of a git object.
+type Hash [20]byte
+
+// ParseHash parses a hash in hexadecimal form.
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	if len(s) != 2*len(h) {
+		return h, fmt.Errorf("git: invalid hash %q", s)
+	}
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("git: invalid hash %q", s)
+	}
+	return h, nil
+}
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// IsZero reports whether the hash is unset.
+func (h Hash) IsZero() bool {
+	return h == Hash{}
+}
@@ -0,0 +1,191 @@
+package git
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var errBadIndex = errors.New("git: invalid index")
+
+// File modes of index and tree entries.
+const (
+	ModeDir     = 0o040000
+	ModeFile    = 0o100644
+	ModeExec    = 0o100755
+	ModeSymlink = 0o120000
+	ModeGitlink = 0o160000
+)
+
+// Index is the staging area of a repository, listing the tracked files.
+type Index struct {
+	Version int
+	Entries []IndexEntry
+	// The modification time of the index file, if read from a file
+	ModTime time.Time
+}
+
+// IndexEntry is a tracked file of the index.
+type IndexEntry struct {
+	Path string // Slash-separated, relative to the working tree
+	Mode uint32
+	Size uint32
+	Hash Hash
+	// The modification time of the file when it was last staged
+	// or refreshed
+	ModTime time.Time
+	// The merge stage, which is non-zero for unmerged files
+	Stage int
+	// Set for files outside a sparse checkout, which are not
+	// in the working tree
+	SkipWorktree bool
+}
+
+// Index reads the index of the repository.
+func (r *Repo) Index() (*Index, error) {
+	name := filepath.Join(r.Dir, "index")
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		// A new repository has no index
+		return &Index{Version: 2}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	idx, err := ParseIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(name); err == nil {
+		idx.ModTime = info.ModTime()
+	}
+	return idx, nil
+}
+
+// ParseIndex parses an index file of version 2, 3 or 4.
+// Extensions are ignored.
+func ParseIndex(data []byte) (*Index, error) {
+	if len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil, errBadIndex
+	}
+	version := int(binary.BigEndian.Uint32(data[4:]))
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("git: unsupported index version %d", version)
+	}
+	n := int(binary.BigEndian.Uint32(data[8:]))
+
+	idx := &Index{Version: version, Entries: make([]IndexEntry, 0, n)}
+	p := 12
+	prev := ""
+	for range n {
+		start := p
+		// Stat data, mode, size, hash and flags
+		if p+62 > len(data) {
+			return nil, errBadIndex
+		}
+		var e IndexEntry
+		e.ModTime = time.Unix(int64(binary.BigEndian.Uint32(data[p+8:])), int64(binary.BigEndian.Uint32(data[p+12:])))
+		e.Mode = binary.BigEndian.Uint32(data[p+24:])
+		e.Size = binary.BigEndian.Uint32(data[p+36:])
+		copy(e.Hash[:], data[p+40:p+60])
+		flags := binary.BigEndian.Uint16(data[p+60:])
+		e.Stage = int(flags>>12) & 3
+		p += 62
+
+		if flags&0x4000 != 0 {
+			if version < 3 || p+2 > len(data) {
+				return nil, errBadIndex
+			}
+			ext := binary.BigEndian.Uint16(data[p:])
+			e.SkipWorktree = ext&0x4000 != 0
+			p += 2
+		}
+
+		if version == 4 {
+			// The path is prefix compressed against the previous one
+			strip, k := readVarint(data[p:])
+			if k == 0 || strip > len(prev) {
+				return nil, errBadIndex
+			}
+			p += k
+			end := bytes.IndexByte(data[p:], 0)
+			if end < 0 {
+				return nil, errBadIndex
+			}
+			e.Path = prev[:len(prev)-strip] + string(data[p:p+end])
+			p += end + 1
+		} else {
+			end := bytes.IndexByte(data[p:], 0)
+			if end < 0 {
+				return nil, errBadIndex
+			}
+			e.Path = string(data[p : p+end])
+			p += end + 1
+			// Entries are padded with NULs to a multiple of 8 bytes
+			p = start + (p-start+7)&^7
+		}
+
+		idx.Entries = append(idx.Entries, e)
+		prev = e.Path
+	}
+	return idx, nil
+}
+
+// Stale reports whether the working tree file of the entry, at the
+// given path, may differ from the entry. Like git, it compares the
+// size and modification time of the file with the entry, and treats
+// files modified after the index was written as stale, since they may
+// have changed in the same instant.
+func (idx *Index) Stale(e IndexEntry, path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return true
+	}
+	if uint32(info.Size()) != e.Size || !info.ModTime().Equal(e.ModTime) {
+		return true
+	}
+	return idx.ModTime.IsZero() || !e.ModTime.Before(idx.ModTime)
+}
+
+// readVarint reads the variable-length offset encoding used by
+// index version 4 and delta objects, returning the value and the
+// number of bytes read, or 0 if invalid.
+func readVarint(data []byte) (int, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	c := data[0]
+	v := int(c & 0x7f)
+	i := 1
+	for c&0x80 != 0 {
+		if i == len(data) || i > 8 {
+			return 0, 0
+		}
+		c = data[i]
+		v = ((v + 1) << 7) | int(c&0x7f)
+		i++
+	}
+	return v, i
+}
+
+// FS returns a file system of the tracked files in the working tree,
+// with the contents read from worktree. Unmerged files are listed once,
+// while submodules and files outside a sparse checkout are left out.
+func (idx *Index) FS(worktree fs.FS) fs.FS {
+	files := make(map[string]fs.FileMode, len(idx.Entries))
+	for _, e := range idx.Entries {
+		if e.SkipWorktree || !fs.ValidPath(e.Path) {
+			continue
+		}
+		switch e.Mode {
+		case ModeFile, ModeExec, ModeSymlink:
+			files[e.Path] = fileMode(e.Mode)
+		}
+	}
+	return newListFS(files, worktree.Open)
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var indexFiles = map[string]string{
+	"main.go":                "package main\n",
+	"README.md":              "# Readme\n",
+	"internal/gen/api.go":    "package gen\n",
+	"internal/gen/api_2.go":  "package gen\n",
+	"internal/lib.go":        "package internal\n",
+	"internal/lib/x.go":      "package lib\n",
+	"docs/guide/a/b/c/d.md":  "deep\n",
+	"with space/file name.c": "int x;\n",
+}
+
+func TestIndex(t *testing.T) {
+	for _, version := range []string{"2", "3", "4"} {
+		t.Run("v"+version, func(t *testing.T) {
+			dir := testRepo(t, indexFiles)
+			runGit(t, dir, "update-index", "--index-version", version)
+			if version == "3" {
+				runGit(t, dir, "update-index", "--skip-worktree", "README.md")
+			}
+
+			repo, err := Open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			idx, err := repo.Index()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, e := range idx.Entries {
+				got = append(got, fmt.Sprintf("%o %s %d\t%s", e.Mode, e.Hash, e.Stage, e.Path))
+			}
+			expect := strings.Split(strings.TrimSpace(runGit(t, dir, "ls-files", "-s")), "\n")
+			if !slices.Equal(got, expect) {
+				t.Fatalf("expected entries\n%s\ngot\n%s", strings.Join(expect, "\n"), strings.Join(got, "\n"))
+			}
+
+			var files []string
+			for name := range indexFiles {
+				if version != "3" || name != "README.md" {
+					files = append(files, name)
+				}
+			}
+			fsys := idx.FS(os.DirFS(dir))
+			if err := fstest.TestFS(fsys, files...); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := fs.Stat(fsys, "README.md"); version == "3" && err == nil {
+				t.Fatal("expected skip-worktree file to be left out")
+			}
+		})
+	}
+}
+
+func TestStale(t *testing.T) {
+	dir := testRepo(t, map[string]string{"a.go": "package a\n", "b.go": "package b\n"})
+	// Files modified after the index was written are stale, so
+	// backdate the files and refresh the index
+	past := time.Now().Add(-time.Hour)
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.Chtimes(filepath.Join(dir, name), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, dir, "update-index", "--really-refresh")
+	writeFiles(t, dir, map[string]string{"b.go": "package b\n\nvar x int\n"})
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := repo.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range idx.Entries {
+		if stale := idx.Stale(e, filepath.Join(dir, e.Path)); stale != (e.Path == "b.go") {
+			t.Errorf("%s: expected stale %v, got %v", e.Path, !stale, stale)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package git
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A listFS is a read-only file system of listed files, with the
+// directories implied by their paths.
+type listFS struct {
+	files map[string]fs.FileMode
+	dirs  map[string][]fs.DirEntry
+	open  func(name string) (fs.File, error)
+}
+
+func newListFS(files map[string]fs.FileMode, open func(name string) (fs.File, error)) *listFS {
+	fsys := &listFS{
+		files: files,
+		dirs:  map[string][]fs.DirEntry{".": nil},
+		open:  open,
+	}
+
+	for name, mode := range files {
+		dir := path.Dir(name)
+		fsys.addDir(dir)
+		fsys.dirs[dir] = append(fsys.dirs[dir], &listEntry{fsys, name, mode})
+	}
+
+	for _, entries := range fsys.dirs {
+		slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+	}
+	return fsys
+}
+
+// addDir adds a directory and its parents, unless already added.
+func (fsys *listFS) addDir(dir string) {
+	if _, ok := fsys.dirs[dir]; ok {
+		return
+	}
+	parent := path.Dir(dir)
+	fsys.addDir(parent)
+	fsys.dirs[dir] = nil
+	fsys.dirs[parent] = append(fsys.dirs[parent], &listEntry{fsys, dir, fs.ModeDir})
+}
+
+func (fsys *listFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if _, ok := fsys.files[name]; ok {
+		return fsys.open(name)
+	}
+	if entries, ok := fsys.dirs[name]; ok {
+		return &listDir{info: dirInfo(path.Base(name)), entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (fsys *listFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	entries, ok := fsys.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return slices.Clone(entries), nil
+}
+
+func (fsys *listFS) Stat(name string) (fs.FileInfo, error) {
+	if _, ok := fsys.dirs[name]; ok {
+		return dirInfo(path.Base(name)), nil
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// listEntry is a file or directory of a listFS.
+type listEntry struct {
+	fsys *listFS
+	path string
+	mode fs.FileMode
+}
+
+func (e *listEntry) Name() string               { return path.Base(e.path) }
+func (e *listEntry) IsDir() bool                { return e.mode.IsDir() }
+func (e *listEntry) Type() fs.FileMode          { return e.mode.Type() }
+func (e *listEntry) Info() (fs.FileInfo, error) { return e.fsys.Stat(e.path) }
+
+// dirInfo describes a directory of a listFS.
+type dirInfo string
+
+func (d dirInfo) Name() string       { return string(d) }
+func (d dirInfo) Size() int64        { return 0 }
+func (d dirInfo) Mode() fs.FileMode  { return fs.ModeDir | 0o555 }
+func (d dirInfo) ModTime() time.Time { return time.Time{} }
+func (d dirInfo) IsDir() bool        { return true }
+func (d dirInfo) Sys() any           { return nil }
+
+// listDir is an open directory of a listFS.
+type listDir struct {
+	info    dirInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *listDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *listDir) Close() error               { return nil }
+
+func (d *listDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: string(d.info), Err: fs.ErrInvalid}
+}
+
+func (d *listDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return slices.Clone(rest), nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return slices.Clone(rest[:n]), nil
+}
@@ -0,0 +1,198 @@
+package git
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// ErrNotFound is returned for missing objects and unknown revisions.
+var ErrNotFound = errors.New("git: not found")
+
+// ObjectType is the type of a git object.
+type ObjectType int
+
+const (
+	BadObject ObjectType = iota
+	CommitObject
+	TreeObject
+	BlobObject
+	TagObject
+)
+
+var typeNames = [...]string{"bad", "commit", "tree", "blob", "tag"}
+
+func (t ObjectType) String() string {
+	if t < 0 || int(t) >= len(typeNames) {
+		return typeNames[0]
+	}
+	return typeNames[t]
+}
+
+func parseType(s string) ObjectType {
+	for i, name := range typeNames[1:] {
+		if s == name {
+			return ObjectType(i + 1)
+		}
+	}
+	return BadObject
+}
+
+// Object is a git object read from a repository.
+type Object struct {
+	Type ObjectType
+	Data []byte
+}
+
+// HashObject returns the hash of an object with the given type and data,
+// like the blob of a file in the working tree.
+func HashObject(t ObjectType, data []byte) Hash {
+	h := sha1.New()
+	fmt.Fprintf(h, "%s %d\x00", t, len(data))
+	h.Write(data)
+	var sum Hash
+	h.Sum(sum[:0])
+	return sum
+}
+
+// objectStore reads the loose and packed objects of a repository.
+// It is safe for concurrent use.
+type objectStore struct {
+	dir   string
+	once  sync.Once
+	packs []*pack
+	err   error
+}
+
+// Object reads an object of the repository.
+func (r *Repo) Object(h Hash) (Object, error) {
+	obj, err := r.store().read(h)
+	if err != nil {
+		return obj, fmt.Errorf("object %s: %w", h, err)
+	}
+	return obj, nil
+}
+
+// Close releases the pack files opened for reading objects.
+func (r *Repo) Close() error {
+	if r.objects == nil {
+		return nil
+	}
+	var errs []error
+	for _, p := range r.objects.packs {
+		errs = append(errs, p.close())
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Repo) store() *objectStore {
+	r.init.Do(func() {
+		r.objects = &objectStore{dir: filepath.Join(r.CommonDir, "objects")}
+	})
+	return r.objects
+}
+
+func (s *objectStore) read(h Hash) (Object, error) {
+	obj, err := s.readLoose(h)
+	if !errors.Is(err, os.ErrNotExist) {
+		return obj, err
+	}
+
+	if err := s.openPacks(); err != nil {
+		return obj, err
+	}
+	for _, p := range s.packs {
+		if off, ok := p.find(h); ok {
+			return p.read(off, s)
+		}
+	}
+	return obj, ErrNotFound
+}
+
+func (s *objectStore) readLoose(h Hash) (Object, error) {
+	hex := h.String()
+	f, err := os.Open(filepath.Join(s.dir, hex[:2], hex[2:]))
+	if err != nil {
+		return Object{}, err
+	}
+	defer f.Close()
+
+	zr, err := zlib.NewReader(f)
+	if err != nil {
+		return Object{}, err
+	}
+	defer zr.Close()
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return Object{}, err
+	}
+
+	// The header is like "blob 12\x00"
+	header, body, ok := bytes.Cut(data, []byte{0})
+	if !ok {
+		return Object{}, errors.New("invalid loose object")
+	}
+	typ, size, _ := bytes.Cut(header, []byte{' '})
+	obj := Object{Type: parseType(string(typ)), Data: body}
+	if n, err := strconv.Atoi(string(size)); err != nil || n != len(body) || obj.Type == BadObject {
+		return Object{}, errors.New("invalid loose object")
+	}
+	return obj, nil
+}
+
+func (s *objectStore) openPacks() error {
+	s.once.Do(func() {
+		idxs, err := filepath.Glob(filepath.Join(s.dir, "pack", "*.idx"))
+		if err != nil {
+			s.err = err
+			return
+		}
+		for _, idx := range idxs {
+			p, err := openPack(idx)
+			if err != nil {
+				s.err = err
+				return
+			}
+			s.packs = append(s.packs, p)
+		}
+	})
+	return s.err
+}
+
+// findPrefix returns the objects with a hexadecimal name starting
+// with prefix.
+func (s *objectStore) findPrefix(prefix string) ([]Hash, error) {
+	var found []Hash
+	add := func(h Hash) {
+		for _, f := range found {
+			if f == h {
+				return
+			}
+		}
+		found = append(found, h)
+	}
+
+	names, _ := filepath.Glob(filepath.Join(s.dir, prefix[:2], prefix[2:]+"*"))
+	for _, name := range names {
+		if h, err := ParseHash(prefix[:2] + filepath.Base(name)); err == nil {
+			add(h)
+		}
+	}
+
+	if err := s.openPacks(); err != nil {
+		return nil, err
+	}
+	for _, p := range s.packs {
+		for _, h := range p.findPrefix(prefix) {
+			add(h)
+		}
+	}
+	return found, nil
+}
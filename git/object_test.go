@@ -0,0 +1,168 @@
+package git
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// commitFiles commits the files with the given message.
+func commitFiles(t *testing.T, dir, msg string, files map[string]string) string {
+	t.Helper()
+	writeFiles(t, dir, files)
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", msg)
+	return strings.TrimSpace(runGit(t, dir, "rev-parse", "HEAD"))
+}
+
+// historyRepo creates a repository with a branch off main.
+func historyRepo(t *testing.T) string {
+	dir := testRepo(t, nil)
+	var lines []string
+	for i := range 200 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	big := strings.Join(lines, "\n") + "\n"
+
+	commitFiles(t, dir, "First", map[string]string{"a.go": "package a\n", "big.txt": big})
+	runGit(t, dir, "tag", "-a", "-m", "Version 1", "v1")
+	commitFiles(t, dir, "Second", map[string]string{"big.txt": strings.Replace(big, "line 100\n", "line 100 changed\n", 1)})
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+	commitFiles(t, dir, "Feature", map[string]string{"b/c.go": "package c\n"})
+	runGit(t, dir, "checkout", "-q", "main")
+	commitFiles(t, dir, "Third", map[string]string{"a.go": "package a\n\n// @todo Third\n"})
+	runGit(t, dir, "checkout", "-q", "feature")
+	return dir
+}
+
+func TestObjects(t *testing.T) {
+	for _, packed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("packed=%v", packed), func(t *testing.T) {
+			dir := historyRepo(t)
+			if packed {
+				runGit(t, dir, "gc", "-q", "--aggressive")
+			}
+			repo, err := Open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer repo.Close()
+
+			for _, rev := range []string{"HEAD", "HEAD~1", "HEAD^", "HEAD~2", "main", "feature", "v1", "v1^0", "main^1~1", "refs/heads/main"} {
+				h, err := repo.Resolve(rev)
+				if err != nil {
+					t.Fatalf("resolve %s: %v", rev, err)
+				}
+				expect := strings.TrimSpace(runGit(t, dir, "rev-parse", rev))
+				if h.String() != expect {
+					t.Errorf("resolve %s: expected %s, got %s", rev, expect, h)
+				}
+			}
+
+			head, _ := repo.Resolve("HEAD")
+			short, err := repo.Resolve(head.String()[:7])
+			if err != nil || short != head {
+				t.Errorf("resolve abbreviated hash: got %s, %v", short, err)
+			}
+
+			// The tag object is peeled to the commit
+			c, err := repo.Commit(mustResolve(t, repo, "v1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Summary() != "First" || c.Author.Name != "Alice" || len(c.Parents) != 0 {
+				t.Errorf("unexpected commit %+v", c)
+			}
+			if expect := strings.TrimSpace(runGit(t, dir, "rev-parse", "v1^{}")); c.Hash.String() != expect {
+				t.Errorf("expected peeled commit %s, got %s", expect, c.Hash)
+			}
+
+			// Merge base of the branches
+			base, err := repo.MergeBase(mustResolve(t, repo, "main"), head)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expect := strings.TrimSpace(runGit(t, dir, "merge-base", "main", "HEAD")); base.String() != expect {
+				t.Errorf("expected merge base %s, got %s", expect, base)
+			}
+
+			// First-parent history down to the merge base
+			commits, err := repo.FirstParents(head, base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var hashes []string
+			for _, c := range commits {
+				hashes = append(hashes, c.Hash.String())
+			}
+			expect := strings.Fields(runGit(t, dir, "rev-list", "--first-parent", "main..HEAD"))
+			if !slices.Equal(hashes, expect) {
+				t.Errorf("expected first parents %q, got %q", expect, hashes)
+			}
+
+			// Files and contents of the trees, including deltified
+			// blobs when packed
+			for _, rev := range []string{"HEAD", "main", "v1"} {
+				files, err := repo.TreeFiles(mustResolve(t, repo, rev))
+				if err != nil {
+					t.Fatal(err)
+				}
+				names := slices.Sorted(maps.Keys(files))
+				expect := strings.Fields(runGit(t, dir, "ls-tree", "-r", "--name-only", rev))
+				if !slices.Equal(names, expect) {
+					t.Errorf("files of %s: expected %q, got %q", rev, expect, names)
+				}
+				for name, e := range files {
+					obj, err := repo.Object(e.Hash)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if data := runGit(t, dir, "cat-file", "blob", e.Hash.String()); string(obj.Data) != data {
+						t.Errorf("contents of %s at %s differ", name, rev)
+					}
+				}
+			}
+		})
+	}
+}
+
+func mustResolve(t *testing.T, repo *Repo, rev string) Hash {
+	t.Helper()
+	h, err := repo.Resolve(rev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestTreeFS(t *testing.T) {
+	dir := historyRepo(t)
+	runGit(t, dir, "gc", "-q")
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	for _, rev := range []string{"HEAD", "v1"} {
+		fsys, err := repo.TreeFS(mustResolve(t, repo, rev))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files := strings.Fields(runGit(t, dir, "ls-tree", "-r", "--name-only", rev))
+		if err := fstest.TestFS(fsys, files...); err != nil {
+			t.Fatal(err)
+		}
+		data, err := fs.ReadFile(fsys, "a.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expect := runGit(t, dir, "show", rev+":a.go"); string(data) != expect {
+			t.Errorf("a.go at %s: expected %q, got %q", rev, expect, data)
+		}
+	}
+}
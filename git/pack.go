@@ -0,0 +1,300 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Types of packed objects, in addition to the object types.
+const (
+	ofsDelta = 6
+	refDelta = 7
+)
+
+// The number of bytes of packed objects cached, as delta bases
+// are often read repeatedly.
+const packCacheSize = 32 << 20
+
+var errBadPack = errors.New("invalid pack")
+
+// A pack is an open pack file with its index.
+type pack struct {
+	file    *os.File
+	idx     []byte
+	n       int
+	names   []byte // Sorted object names
+	offsets []byte
+	large   []byte // 64-bit offsets
+
+	mu        sync.Mutex
+	cache     map[int64]Object
+	cacheSize int
+}
+
+// openPack opens the version 2 index file and the corresponding pack file.
+func openPack(idxPath string) (*pack, error) {
+	idx, err := os.ReadFile(idxPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(idx) < 8+256*4 || string(idx[:4]) != "\xfftOc" || binary.BigEndian.Uint32(idx[4:]) != 2 {
+		return nil, fmt.Errorf("%s: unsupported pack index", idxPath)
+	}
+
+	p := &pack{idx: idx, cache: make(map[int64]Object)}
+	p.n = int(binary.BigEndian.Uint32(idx[8+255*4:]))
+	names := 8 + 256*4
+	offsets := names + p.n*(20+4)
+	if len(idx) < offsets+p.n*4 {
+		return nil, fmt.Errorf("%s: %w", idxPath, errBadPack)
+	}
+	p.names = idx[names : names+p.n*20]
+	p.offsets = idx[offsets : offsets+p.n*4]
+	p.large = idx[offsets+p.n*4:]
+
+	p.file, err = os.Open(strings.TrimSuffix(idxPath, ".idx") + ".pack")
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *pack) close() error {
+	return p.file.Close()
+}
+
+// fanout returns the range of object names starting with the byte b.
+func (p *pack) fanout(b byte) (int, int) {
+	lo := 0
+	if b > 0 {
+		lo = int(binary.BigEndian.Uint32(p.idx[8+(int(b)-1)*4:]))
+	}
+	hi := int(binary.BigEndian.Uint32(p.idx[8+int(b)*4:]))
+	return lo, hi
+}
+
+func (p *pack) name(i int) []byte {
+	return p.names[i*20 : (i+1)*20]
+}
+
+// find returns the offset of an object in the pack file.
+func (p *pack) find(h Hash) (int64, bool) {
+	lo, hi := p.fanout(h[0])
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch bytes.Compare(p.name(mid), h[:]) {
+		case 0:
+			return p.offset(mid), true
+		case -1:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, false
+}
+
+func (p *pack) offset(i int) int64 {
+	off := binary.BigEndian.Uint32(p.offsets[i*4:])
+	if off&0x80000000 == 0 {
+		return int64(off)
+	}
+	i = int(off & 0x7fffffff)
+	if len(p.large) < (i+1)*8 {
+		return -1
+	}
+	return int64(binary.BigEndian.Uint64(p.large[i*8:]))
+}
+
+// findPrefix returns the objects with a hexadecimal name starting
+// with prefix, which is at least two characters long.
+func (p *pack) findPrefix(prefix string) []Hash {
+	first, err := hex.DecodeString(prefix[:2])
+	if err != nil {
+		return nil
+	}
+	var found []Hash
+	lo, hi := p.fanout(first[0])
+	for i := lo; i < hi; i++ {
+		if name := p.name(i); strings.HasPrefix(hex.EncodeToString(name), prefix) {
+			found = append(found, Hash(name))
+		}
+	}
+	return found
+}
+
+// read reads the object at the offset, resolving deltas.
+func (p *pack) read(off int64, s *objectStore) (Object, error) {
+	if off < 0 {
+		return Object{}, errBadPack
+	}
+	p.mu.Lock()
+	obj, ok := p.cache[off]
+	p.mu.Unlock()
+	if ok {
+		return obj, nil
+	}
+
+	rd := bufio.NewReader(io.NewSectionReader(p.file, off, 1<<62))
+	c, err := rd.ReadByte()
+	if err != nil {
+		return obj, err
+	}
+	typ := int(c>>4) & 7
+	size := int(c & 0x0f)
+	for shift := 4; c&0x80 != 0; shift += 7 {
+		if c, err = rd.ReadByte(); err != nil {
+			return obj, err
+		}
+		size |= int(c&0x7f) << shift
+	}
+
+	var base Object
+	switch typ {
+	case ofsDelta:
+		// The base is at a negative offset from the object
+		var buf [9]byte
+		n := 0
+		for {
+			if n == len(buf) {
+				return obj, errBadPack
+			}
+			if buf[n], err = rd.ReadByte(); err != nil {
+				return obj, err
+			}
+			n++
+			if buf[n-1]&0x80 == 0 {
+				break
+			}
+		}
+		rel, _ := readVarint(buf[:n])
+		if base, err = p.read(off-int64(rel), s); err != nil {
+			return obj, err
+		}
+	case refDelta:
+		var h Hash
+		if _, err := io.ReadFull(rd, h[:]); err != nil {
+			return obj, err
+		}
+		if base, err = s.read(h); err != nil {
+			return obj, err
+		}
+	}
+
+	data, err := inflate(rd, size)
+	if err != nil {
+		return obj, err
+	}
+	switch typ {
+	case ofsDelta, refDelta:
+		if data, err = applyDelta(base.Data, data); err != nil {
+			return obj, err
+		}
+		obj = Object{base.Type, data}
+	case int(CommitObject), int(TreeObject), int(BlobObject), int(TagObject):
+		obj = Object{ObjectType(typ), data}
+	default:
+		return obj, errBadPack
+	}
+
+	p.mu.Lock()
+	if p.cacheSize+len(obj.Data) > packCacheSize {
+		clear(p.cache)
+		p.cacheSize = 0
+	}
+	p.cache[off] = obj
+	p.cacheSize += len(obj.Data)
+	p.mu.Unlock()
+	return obj, nil
+}
+
+func inflate(r io.Reader, size int) ([]byte, error) {
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(zr, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// applyDelta reconstructs an object from its base and a delta.
+func applyDelta(base, delta []byte) ([]byte, error) {
+	srcSize, delta := deltaSize(delta)
+	dstSize, delta := deltaSize(delta)
+	if srcSize != len(base) {
+		return nil, errBadPack
+	}
+
+	out := make([]byte, 0, dstSize)
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+
+		if op&0x80 == 0 {
+			// Insert the following bytes
+			n := int(op)
+			if n == 0 || n > len(delta) {
+				return nil, errBadPack
+			}
+			out = append(out, delta[:n]...)
+			delta = delta[n:]
+			continue
+		}
+
+		// Copy from the base, with the offset and size given by
+		// the bytes flagged in the opcode
+		var off, n int
+		for i := range 7 {
+			if op&(1<<i) == 0 {
+				continue
+			}
+			if len(delta) == 0 {
+				return nil, errBadPack
+			}
+			if i < 4 {
+				off |= int(delta[0]) << (8 * i)
+			} else {
+				n |= int(delta[0]) << (8 * (i - 4))
+			}
+			delta = delta[1:]
+		}
+		if n == 0 {
+			n = 0x10000
+		}
+		if off+n > len(base) {
+			return nil, errBadPack
+		}
+		out = append(out, base[off:off+n]...)
+	}
+
+	if len(out) != dstSize {
+		return nil, errBadPack
+	}
+	return out, nil
+}
+
+// deltaSize reads a little-endian base-128 size of a delta header.
+func deltaSize(delta []byte) (int, []byte) {
+	size := 0
+	for i, shift := 0, 0; i < len(delta); i, shift = i+1, shift+7 {
+		size |= int(delta[i]&0x7f) << shift
+		if delta[i]&0x80 == 0 {
+			return size, delta[i+1:]
+		}
+	}
+	return -1, nil
+}
@@ -0,0 +1,196 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Resolve returns the object named by a revision, like a full or
+// abbreviated hash, HEAD, a branch, a tag or a remote branch, optionally
+// followed by ancestry suffixes like `~2`, `^` or `^2`.
+func (r *Repo) Resolve(rev string) (Hash, error) {
+	name, suffix := rev, ""
+	if i := strings.IndexAny(rev, "~^"); i >= 0 {
+		name, suffix = rev[:i], rev[i:]
+	}
+
+	h, err := r.resolveName(name)
+	if err != nil {
+		return h, err
+	}
+
+	for suffix != "" {
+		op := suffix[0]
+		suffix = suffix[1:]
+		j := 0
+		for j < len(suffix) && suffix[j] >= '0' && suffix[j] <= '9' {
+			j++
+		}
+		n := 1
+		if j > 0 {
+			n, _ = strconv.Atoi(suffix[:j])
+		}
+		suffix = suffix[j:]
+
+		switch op {
+		case '~':
+			// The n-th first-parent ancestor
+			for range n {
+				if h, err = r.parent(h, 1, rev); err != nil {
+					return h, err
+				}
+			}
+		case '^':
+			// The n-th parent, or the commit itself for ^0
+			if n == 0 {
+				c, err := r.Commit(h)
+				if err != nil {
+					return h, err
+				}
+				h = c.Hash
+			} else if h, err = r.parent(h, n, rev); err != nil {
+				return h, err
+			}
+		}
+	}
+	return h, nil
+}
+
+func (r *Repo) parent(h Hash, n int, rev string) (Hash, error) {
+	c, err := r.Commit(h)
+	if err != nil {
+		return h, err
+	}
+	if n > len(c.Parents) {
+		return h, fmt.Errorf("%w: revision %s", ErrNotFound, rev)
+	}
+	return c.Parents[n-1], nil
+}
+
+func (r *Repo) resolveName(name string) (Hash, error) {
+	if h, err := ParseHash(name); err == nil {
+		return h, nil
+	}
+
+	for _, ref := range []string{
+		name,
+		"refs/" + name,
+		"refs/tags/" + name,
+		"refs/heads/" + name,
+		"refs/remotes/" + name,
+		"refs/remotes/" + name + "/HEAD",
+	} {
+		h, err := r.Ref(ref)
+		if err == nil {
+			return h, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return h, err
+		}
+	}
+
+	// An abbreviated hash
+	if len(name) >= 4 && isHex(name) {
+		found, err := r.store().findPrefix(strings.ToLower(name))
+		if err != nil {
+			return Hash{}, err
+		}
+		switch len(found) {
+		case 1:
+			return found[0], nil
+		case 0:
+		default:
+			return Hash{}, fmt.Errorf("git: ambiguous revision %s", name)
+		}
+	}
+	return Hash{}, fmt.Errorf("%w: revision %s", ErrNotFound, name)
+}
+
+// Ref returns the object a reference, like HEAD or refs/heads/main,
+// points to, following symbolic references.
+func (r *Repo) Ref(name string) (Hash, error) {
+	for range 8 {
+		target, err := r.readRef(name)
+		if err != nil {
+			return Hash{}, err
+		}
+		sym, ok := strings.CutPrefix(target, "ref: ")
+		if !ok {
+			return ParseHash(target)
+		}
+		name = sym
+	}
+	return Hash{}, fmt.Errorf("git: too many symbolic references at %s", name)
+}
+
+// readRef reads the target of a loose or packed reference.
+func (r *Repo) readRef(name string) (string, error) {
+	if strings.Contains(name, "..") {
+		return "", fmt.Errorf("%w: reference %s", ErrNotFound, name)
+	}
+
+	// HEAD and other pseudo references are specific to the
+	// working tree, while others are shared
+	dir := r.CommonDir
+	if !strings.HasPrefix(name, "refs/") {
+		dir = r.Dir
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) && !isDirErr(err) {
+		return "", err
+	}
+
+	f, err := os.Open(filepath.Join(r.CommonDir, "packed-refs"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%w: reference %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+		hash, ref, _ := strings.Cut(line, " ")
+		if ref == name {
+			return hash, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%w: reference %s", ErrNotFound, name)
+}
+
+// isDirErr reports whether reading a file failed because it is
+// a directory, like refs/heads for a branch named heads.
+func isDirErr(err error) bool {
+	var pe *os.PathError
+	if errors.As(err, &pe) {
+		if info, err := os.Stat(pe.Path); err == nil && info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+// Package git reads git repositories directly from disk, without
+// requiring the git binary.
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNotRepo is returned when no repository is found.
+var ErrNotRepo = errors.New("git: not a git repository")
+
+// Repo is a git repository with a working tree.
+type Repo struct {
+	// The git directory, like .git, holding the index and HEAD
+	Dir string
+	// The directory holding the objects and refs, which is
+	// shared by linked working trees
+	CommonDir string
+	// The root of the working tree
+	WorkTree string
+
+	init    sync.Once
+	objects *objectStore
+}
+
+// Find returns the repository containing the path, searching
+// the path and its parent directories.
+func Find(path string) (*Repo, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		if repo, err := Open(dir); err == nil {
+			return repo, nil
+		} else if !errors.Is(err, ErrNotRepo) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("%w: %s", ErrNotRepo, path)
+		}
+		dir = parent
+	}
+}
+
+// Open returns the repository with its working tree rooted at dir.
+func Open(dir string) (*Repo, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	gitDir := filepath.Join(dir, ".git")
+	info, err := os.Stat(gitDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotRepo, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Linked working trees and submodules have a .git file
+	// pointing to the git directory
+	if !info.IsDir() {
+		data, err := os.ReadFile(gitDir)
+		if err != nil {
+			return nil, err
+		}
+		target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+		if !ok {
+			return nil, fmt.Errorf("git: invalid .git file in %s", dir)
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, target)
+		}
+		gitDir = filepath.Clean(target)
+	}
+
+	repo := &Repo{Dir: gitDir, CommonDir: gitDir, WorkTree: dir}
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		repo.CommonDir = filepath.Clean(common)
+	}
+	return repo, nil
+}
+
+// Rel returns the slash-separated path of a file or directory
+// relative to the working tree, or "." for the root.
+func (r *Repo) Rel(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(r.WorkTree, abs)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("git: %s is outside the working tree %s", path, r.WorkTree)
+	}
+	return filepath.ToSlash(rel), nil
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// TreeFS returns a read-only file system of the files of a tree, or of
+// the tree of a commit or tag, like a checkout of a revision. Contents
+// are read from the repository when the files are opened. Submodules
+// are left out.
+func (r *Repo) TreeFS(h Hash) (fs.FS, error) {
+	files, err := r.TreeFiles(h)
+	if err != nil {
+		return nil, err
+	}
+
+	modes := make(map[string]fs.FileMode, len(files))
+	for name, e := range files {
+		modes[name] = fileMode(e.Mode)
+	}
+	open := func(name string) (fs.File, error) {
+		e := files[name]
+		obj, err := r.Object(e.Hash)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		info := blobInfo{path.Base(name), int64(len(obj.Data)), fileMode(e.Mode)}
+		return &blobFile{bytes.NewReader(obj.Data), info}, nil
+	}
+	return newListFS(modes, open), nil
+}
+
+// fileMode returns the file mode of a tree or index entry mode.
+func fileMode(mode uint32) fs.FileMode {
+	switch mode {
+	case ModeExec:
+		return 0o755
+	case ModeSymlink:
+		return fs.ModeSymlink | 0o777
+	default:
+		return 0o644
+	}
+}
+
+// blobFile is an open file of a TreeFS.
+type blobFile struct {
+	*bytes.Reader
+	info blobInfo
+}
+
+func (f *blobFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *blobFile) Close() error               { return nil }
+
+type blobInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (i blobInfo) Name() string       { return i.name }
+func (i blobInfo) Size() int64        { return i.size }
+func (i blobInfo) Mode() fs.FileMode  { return i.mode }
+func (i blobInfo) ModTime() time.Time { return time.Time{} }
+func (i blobInfo) IsDir() bool        { return false }
+func (i blobInfo) Sys() any           { return nil }
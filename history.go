@@ -0,0 +1,208 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MHmorgan/reminders/git"
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+	"github.com/MHmorgan/reminders/tio"
+)
+
+// A snapshot is the reminders of a sampled commit.
+type snapshot struct {
+	commit    *git.Commit
+	reminders map[string]reminder.Reminder // By fingerprint
+	counts    map[string]int               // By tag group
+}
+
+// Run the history command, which reports the reminders of commits
+// sampled over a range of the first-parent history.
+func runHistory(args []string) {
+	fset := flag.NewFlagSet("history", flag.ExitOnError)
+	samples := fset.Int("samples", 10, "report `n` commits sampled evenly over the range, or every commit if 0")
+	scanning := scanFlags(fset)
+	quiet := fset.Bool("q", false, "only print the counts, not the introduced and removed reminders")
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage: reminders history [flags] [rev | from..to] [@tag ...]\n\n")
+		fmt.Fprintf(fset.Output(), "Report the reminders of commits in the first-parent history of rev,\nor of the commits after from up to to, defaulting to HEAD.\n\n")
+		fset.PrintDefaults()
+	}
+	fset.Parse(args)
+
+	var tags, revs []string
+	for _, arg := range fset.Args() {
+		if strings.HasPrefix(arg, "@") {
+			tags = append(tags, arg)
+		} else {
+			revs = append(revs, arg)
+		}
+	}
+	if len(revs) > 1 {
+		fset.Usage()
+		os.Exit(2)
+	}
+
+	cfg, opts := scanning.load()
+
+	repo, err := git.Find(".")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer repo.Close()
+
+	rng := "HEAD"
+	if len(revs) == 1 {
+		rng = revs[0]
+	}
+	commits, err := commitRange(repo, rng)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slices.Reverse(commits)
+	sampled := sample(commits, *samples)
+
+	f := filter{tags: normalizeTags(tags, opts.Aliases)}
+	srch := newSearcher(cfg)
+
+	snapshots := make([]snapshot, 0, len(sampled))
+	for _, c := range sampled {
+		tree, err := repo.TreeFS(c.Tree)
+		if err != nil {
+			log.Fatal(err)
+		}
+		snap := snapshot{
+			commit:    c,
+			reminders: make(map[string]reminder.Reminder),
+			counts:    make(map[string]int),
+		}
+		var t tally
+		for r := range f.matching(scanner.Scan(nWorkers(), opts, srch.Search(tree)), &t) {
+			snap.reminders[r.Fingerprint()] = r
+			snap.counts[groupOf(r, f.tags)]++
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	fmt.Printf("\nHistory of %d commits, %d sampled.\n", len(commits), len(sampled))
+	printCounts(snapshots)
+	if !*quiet {
+		printChanges(snapshots)
+	}
+}
+
+// Returns the commits of a range like from..to, or of the full
+// first-parent history of a revision.
+func commitRange(repo *git.Repo, rng string) ([]*git.Commit, error) {
+	from, to, isRange := strings.Cut(rng, "..")
+	if !isRange {
+		from, to = "", rng
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+
+	head, err := repo.Resolve(to)
+	if err != nil {
+		return nil, err
+	}
+	var stop git.Hash
+	if from != "" {
+		base, err := repo.Resolve(from)
+		if err != nil {
+			return nil, err
+		}
+		if stop, err = repo.MergeBase(base, head); err != nil {
+			return nil, err
+		}
+	}
+	return repo.FirstParents(head, stop)
+}
+
+// Returns at most n commits, sampled evenly including the first and
+// the last, or all commits if n is 0.
+func sample(commits []*git.Commit, n int) []*git.Commit {
+	if n <= 0 || len(commits) <= n {
+		return commits
+	}
+	if n == 1 {
+		return commits[len(commits)-1:]
+	}
+	sampled := make([]*git.Commit, n)
+	for i := range n {
+		sampled[i] = commits[i*(len(commits)-1)/(n-1)]
+	}
+	return sampled
+}
+
+// Print the number of reminders of each tag group per snapshot.
+func printCounts(snapshots []snapshot) {
+	groups := make(map[string]bool)
+	for _, s := range snapshots {
+		for g := range s.counts {
+			groups[g] = true
+		}
+	}
+	columns := slices.Sorted(maps.Keys(groups))
+
+	fmt.Printf("\n%s%-8s %-10s", tio.Bold, "commit", "date")
+	for _, g := range columns {
+		fmt.Printf(" %*s", max(len(g), 3), g)
+	}
+	fmt.Printf(" %5s%s\n", "total", tio.Reset)
+
+	for _, s := range snapshots {
+		fmt.Printf("%s%-8s%s %-10s", tio.Dim, s.commit.Hash.String()[:7], tio.Reset, s.commit.Committer.When.Format(time.DateOnly))
+		total := 0
+		for _, g := range columns {
+			fmt.Printf(" %*d", max(len(g), 3), s.counts[g])
+			total += s.counts[g]
+		}
+		fmt.Printf(" %5d\n", total)
+	}
+}
+
+// Print the reminders introduced and removed between the snapshots.
+func printChanges(snapshots []snapshot) {
+	for i := 1; i < len(snapshots); i++ {
+		prev, cur := snapshots[i-1], snapshots[i]
+		added := changedReminders(cur.reminders, prev.reminders)
+		removed := changedReminders(prev.reminders, cur.reminders)
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		c := cur.commit
+		fmt.Printf("\n%s%s%s %s %s\n", tio.Bold, c.Hash.String()[:7], tio.Reset, c.Committer.When.Format(time.DateOnly), c.Summary())
+		for _, r := range added {
+			fmt.Printf("  %s+%s %s%s:%d%s: %s\n", tio.FgGreen, tio.Reset, tio.Dim, r.File(), r.Line(), tio.Reset, r.Format())
+		}
+		for _, r := range removed {
+			fmt.Printf("  %s-%s %s%s:%d%s: %s\n", tio.FgRed, tio.Reset, tio.Dim, r.File(), r.Line(), tio.Reset, r.Format())
+		}
+	}
+}
+
+// Returns the reminders of a which are not in b, sorted by location.
+func changedReminders(a, b map[string]reminder.Reminder) []reminder.Reminder {
+	var changed []reminder.Reminder
+	for key, r := range a {
+		if _, ok := b[key]; !ok {
+			changed = append(changed, r)
+		}
+	}
+	slices.SortFunc(changed, func(x, y reminder.Reminder) int {
+		if c := strings.Compare(x.File(), y.File()); c != 0 {
+			return c
+		}
+		return x.Line() - y.Line()
+	})
+	return changed
+}
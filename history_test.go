@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MHmorgan/reminders/git"
+	"github.com/MHmorgan/reminders/reminder"
+)
+
+// historyRepo creates a repository with the commits c1, c2 and c3 on
+// main, tagged v1 to v3, and a feature branch from c2 with the commit
+// f1, using the git binary. The test is skipped if git is not
+// installed.
+func historyRepo(t *testing.T) *git.Repo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Alice",
+			"GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=Alice",
+			"GIT_COMMITTER_EMAIL=alice@example.com",
+			"GIT_CONFIG_GLOBAL=/dev/null",
+			"GIT_CONFIG_NOSYSTEM=1",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	commit := func(name string) {
+		t.Helper()
+		data := fmt.Sprintf("// @todo %s\n", name)
+		if err := os.WriteFile(filepath.Join(dir, name+".go"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", "-A")
+		run("commit", "-q", "-m", name)
+	}
+
+	run("init", "-q", "-b", "main")
+	for i, name := range []string{"c1", "c2", "c3"} {
+		commit(name)
+		run("tag", fmt.Sprintf("v%d", i+1))
+	}
+	run("checkout", "-q", "-b", "feature", "v2")
+	commit("f1")
+	run("checkout", "-q", "main")
+
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+var commitRangeTests = []struct {
+	rng     string
+	commits []string
+}{
+	{rng: "HEAD", commits: []string{"c3", "c2", "c1"}},
+	{rng: "v2", commits: []string{"c2", "c1"}},
+	{rng: "", commits: []string{"c3", "c2", "c1"}},
+	{rng: "v1..v3", commits: []string{"c3", "c2"}},
+	{rng: "v2..", commits: []string{"c3"}},
+	{rng: "..v2", commits: []string{"c2", "c1"}},
+	{rng: "main..feature", commits: []string{"f1"}},
+	{rng: "feature..main", commits: []string{"c3"}},
+}
+
+func TestCommitRange(t *testing.T) {
+	repo := historyRepo(t)
+	for _, tt := range commitRangeTests {
+		t.Run(tt.rng, func(t *testing.T) {
+			commits, err := commitRange(repo, tt.rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var summaries []string
+			for _, c := range commits {
+				summaries = append(summaries, c.Summary())
+			}
+			if !slices.Equal(summaries, tt.commits) {
+				t.Fatalf("expected commits %v, got %v", tt.commits, summaries)
+			}
+		})
+	}
+
+	if _, err := commitRange(repo, "nope..main"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
+
+func TestSample(t *testing.T) {
+	commits := make([]*git.Commit, 10)
+	for i := range commits {
+		commits[i] = &git.Commit{Message: fmt.Sprint(i)}
+	}
+	messages := func(sampled []*git.Commit) []string {
+		var msgs []string
+		for _, c := range sampled {
+			msgs = append(msgs, c.Message)
+		}
+		return msgs
+	}
+
+	tests := []struct {
+		n       int
+		sampled []string
+	}{
+		{n: 0, sampled: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+		{n: 1, sampled: []string{"9"}},
+		{n: 2, sampled: []string{"0", "9"}},
+		{n: 4, sampled: []string{"0", "3", "6", "9"}},
+		{n: 10, sampled: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+		{n: 20, sampled: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.n), func(t *testing.T) {
+			sampled := messages(sample(commits, tt.n))
+			if !slices.Equal(sampled, tt.sampled) {
+				t.Fatalf("expected commits %v, got %v", tt.sampled, sampled)
+			}
+		})
+	}
+}
+
+func TestChangedReminders(t *testing.T) {
+	a := map[string]reminder.Reminder{
+		"k1": reminder.New("b.go", 2, "todo b", nil, nil),
+		"k2": reminder.New("a.go", 9, "todo a9", nil, nil),
+		"k3": reminder.New("a.go", 3, "todo a3", nil, nil),
+		"k4": reminder.New("a.go", 1, "todo kept", nil, nil),
+	}
+	b := map[string]reminder.Reminder{
+		"k4": reminder.New("a.go", 5, "todo kept", nil, nil),
+		"k5": reminder.New("c.go", 1, "todo new", nil, nil),
+	}
+
+	var texts []string
+	for _, r := range changedReminders(a, b) {
+		texts = append(texts, r.Text())
+	}
+	expected := []string{"todo a3", "todo a9", "todo b"}
+	if !slices.Equal(texts, expected) {
+		t.Fatalf("expected reminders %q, got %q", expected, texts)
+	}
+}
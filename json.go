@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+)
+
+// summary is the JSON form of the scan summary.
+type summary struct {
+	Files int    `json:"files"`
+	Lines uint64 `json:"lines"`
+}
+
+// Print the received reminders as JSON, using the given filter: one
+// object with a list of reminders and the summary, or, for ndjson,
+// one object per line for each reminder followed by the summary.
+// Returns the number of printed reminders which are overdue.
+func printJSON(
+	w io.Writer,
+	f filter,
+	scanRes <-chan scanner.Result,
+	ndjson bool,
+) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	var t tally
+	reminders := []reminder.Reminder{}
+	for r := range f.matching(scanRes, &t) {
+		if !ndjson {
+			reminders = append(reminders, r)
+		} else if err := enc.Encode(r); err != nil {
+			return t.overdue, err
+		}
+	}
+
+	sum := summary{t.files, scanner.ScannedLines.Load()}
+	var err error
+	if ndjson {
+		err = enc.Encode(struct {
+			Summary summary `json:"summary"`
+		}{sum})
+	} else {
+		enc.SetIndent("", "  ")
+		err = enc.Encode(struct {
+			Reminders []reminder.Reminder `json:"reminders"`
+			Summary   summary             `json:"summary"`
+		}{reminders, sum})
+	}
+	if err != nil {
+		return t.overdue, err
+	}
+	return t.overdue, bw.Flush()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+)
+
+// jsonLine is the decoded form of a reminder or summary line.
+type jsonLine struct {
+	File    string
+	Line    int
+	Text    string
+	Tags    []string
+	Summary *summary
+}
+
+// jsonReminders returns scan results of reminders in two files.
+func jsonReminders() <-chan scanner.Result {
+	return scanResults(
+		reminder.New("a.go", 1, "todo Refactor", []string{"todo"}, []reminder.Span{{Start: 0, End: 4}}),
+		reminder.New("a.go", 5, "later Someday", []string{"later"}, []reminder.Span{{Start: 0, End: 5}}),
+		reminder.New("b.go", 2, "fix Broken", []string{"fix"}, []reminder.Span{{Start: 0, End: 3}}),
+	)
+}
+
+func TestNDJSON(t *testing.T) {
+	scanner.ScannedLines.Store(42)
+	t.Cleanup(func() { scanner.ScannedLines.Store(0) })
+
+	f := filter{tags: normalizeTags([]string{"todo", "fix"}, nil)}
+	var buf bytes.Buffer
+	if _, err := printJSON(&buf, f, jsonReminders(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	var got []jsonLine
+	for _, line := range lines {
+		var l jsonLine
+		if err := json.Unmarshal([]byte(line), &l); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		got = append(got, l)
+	}
+
+	if got[0].File != "a.go" || got[0].Line != 1 || got[0].Text != "todo Refactor" || !slices.Equal(got[0].Tags, []string{"todo"}) {
+		t.Errorf("unexpected first reminder %+v", got[0])
+	}
+	if got[1].File != "b.go" || got[1].Line != 2 || got[1].Text != "fix Broken" {
+		t.Errorf("unexpected second reminder %+v", got[1])
+	}
+	sum := got[2].Summary
+	if sum == nil || got[2].File != "" {
+		t.Fatalf("expected the summary last, got %q", lines[2])
+	}
+	if sum.Files != 2 || sum.Lines != 42 {
+		t.Errorf("expected 2 files and 42 lines, got %+v", *sum)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	scanner.ScannedLines.Store(42)
+	t.Cleanup(func() { scanner.ScannedLines.Store(0) })
+
+	var buf bytes.Buffer
+	if _, err := printJSON(&buf, filter{tags: normalizeTags(nil, nil)}, jsonReminders(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Reminders []jsonLine
+		Summary   summary
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	var locs []string
+	for _, r := range out.Reminders {
+		locs = append(locs, r.File+":"+r.Text)
+	}
+	expected := []string{"a.go:todo Refactor", "a.go:later Someday", "b.go:fix Broken"}
+	if !slices.Equal(locs, expected) {
+		t.Errorf("expected reminders %q, got %q", expected, locs)
+	}
+	if out.Summary.Files != 2 || out.Summary.Lines != 42 {
+		t.Errorf("expected 2 files and 42 lines, got %+v", out.Summary)
+	}
+
+	// Without reminders, the list is empty rather than null
+	buf.Reset()
+	if _, err := printJSON(&buf, filter{tags: normalizeTags(nil, nil)}, scanResults(), false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"reminders": []`) {
+		t.Errorf("expected an empty list of reminders, got\n%s", buf.String())
+	}
+}
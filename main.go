@@ -3,9 +3,13 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/MHmorgan/reminders/scanner"
 	"github.com/MHmorgan/reminders/searcher"
@@ -15,6 +19,25 @@ const version = "1.0.2"
 
 var (
 	printVersion = flag.Bool("version", false, "print app version")
+	assignee     = flag.String("assignee", "", "only print reminders assigned to `name`, like @todo(name)")
+	priority     = flag.Int("priority", 0, "only print reminders with priority `p` or higher, like @p(1)")
+	listDue      = flag.Bool("due", false, "only print reminders with a due date, sorted by due date")
+	failOverdue  = flag.Bool("fail-overdue", false, "exit with status 1 if any printed reminder is overdue")
+	noIgnore     = flag.Bool("no-ignore", false, "don't honor .gitignore, .ignore and .remindersignore files")
+	useGit       = flag.Bool("git", false, "list the files tracked by git from the index instead of walking directories")
+	untracked    = flag.Bool("untracked", false, "with -git, also scan untracked files which are not ignored")
+	filesFrom    = flag.String("files-from", "", "scan the files listed in `file`, one per line, instead of searching (- for stdin)")
+	nulSep       = flag.Bool("0", false, "paths read with -files-from are separated by NUL instead of newline")
+	revision     = flag.String("rev", "", "scan the files of the git `rev`, like a tag, instead of the working tree")
+	since        = flag.String("since", "", "only scan tracked files changed since the branch left the git `rev`, like main, and print reminders on added lines")
+	dryRun       = flag.Bool("dry-run", false, "list the files which would be scanned, and why, without scanning them")
+	showBlame    = flag.Bool("blame", false, "print the author and date of the commit which introduced each reminder")
+	author       = flag.String("author", "", "only print reminders introduced by an author whose name or email contains `name`")
+	olderThan    = flag.String("older-than", "", "only print reminders introduced longer than `age` ago, like 90d, 2w or 1y")
+	sortBy       = flag.String("sort", "file", "sort reminders by `key`: file, age (oldest first) or author")
+	format       = flag.String("format", "text", "print reminders in `format`: text, grep (file:line:col), json, ndjson or sarif")
+	tmplText     = flag.String("template", "", "print each reminder with the Go text/template `tmpl`, given inline or as a file")
+	groupTags    = flag.Bool("group", false, "group reminders by the top-level segment of their tag, like bug for bug/ui")
 	cpuprofile   = flag.String("cpuprofile", "", "write cpu profile to `file`")
 	memprofile   = flag.String("memprofile", "", "write memory profile to `file`")
 )
@@ -24,27 +47,28 @@ var (
 // @Next @Use lipgloss/bubbletea for application output
 // @Todo @Handle formatting only when printing
 
-var include = map[string]bool{
-	".bash": true,
-	".c":    true,
-	".cpp":  true,
-	".css":  true,
-	".go":   true,
-	".html": true,
-	".java": true,
-	".js":   true,
-	".kt":   true,
-	".lua":  true,
-	".nu":   true,
-	".pl":   true,
-	".py":   true,
-	".rb":   true,
-	".rs":   true,
-	".sql":  true,
-	".ts":   true,
-	".yaml": true,
-	".zig":  true,
-	".zsh":  true,
+// Configuration and scanning flags
+var scanning = scanFlags(flag.CommandLine)
+
+// Include and exclude rules given by flags, in order
+var rules []searcher.Rule
+
+// Tags given by flags
+var tagFlags []string
+
+func init() {
+	flag.Func("tag", "only print reminders with `tag`, like @tag on the command line (repeatable)", func(s string) error {
+		tagFlags = append(tagFlags, s)
+		return nil
+	})
+	flag.Func("include", "scan files matching the glob `pattern`, like docs/**/*.md (repeatable)", func(s string) error {
+		rules = append(rules, searcher.Rule{Pattern: s})
+		return nil
+	})
+	flag.Func("exclude", "don't scan files matching the glob `pattern`, like **/*_test.go (repeatable)", func(s string) error {
+		rules = append(rules, searcher.Rule{Pattern: s, Exclude: true})
+		return nil
+	})
 }
 
 var exclude = map[string]bool{
@@ -59,6 +83,17 @@ var exclude = map[string]bool{
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		case "report":
+			runReport(os.Args[2:])
+			return
+		}
+	}
+	flag.Usage = usage
 	flag.Parse()
 
 	if *printVersion {
@@ -70,21 +105,268 @@ func main() {
 		startCpuProfiling(*cpuprofile)
 	}
 
-	cwd, err := os.Getwd()
-	if err != nil {
-		log.Fatal(err)
+	cfg, opts := scanning.load()
+
+	roots, tags := parseArgs(flag.Args())
+	switch *sortBy {
+	case "file", "age", "author":
+	default:
+		log.Fatalf("invalid sort key %q", *sortBy)
+	}
+	switch *format {
+	case "text", "grep", "json", "ndjson", "sarif":
+	default:
+		log.Fatalf("invalid format %q", *format)
+	}
+	// Only the text format is grouped and sorted
+	switch {
+	case *format == "text":
+	case *groupTags:
+		log.Fatalf("-group can't be combined with -format %s", *format)
+	case *sortBy != "file":
+		log.Fatalf("-sort can't be combined with -format %s", *format)
 	}
-	fsys := os.DirFS(cwd)
 
-	srch := searcher.New(include, exclude)
-	srchRes := srch.Search(fsys)
+	var tmpl *template.Template
+	if *tmplText != "" {
+		switch {
+		case *format != "text":
+			log.Fatal("-template can't be combined with -format")
+		case *groupTags:
+			log.Fatal("-template can't be combined with -group")
+		case *sortBy != "file":
+			log.Fatal("-template can't be combined with -sort")
+		}
+		var err error
+		if tmpl, err = parseTemplate(*tmplText); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	srch := newSearcher(cfg)
+	if *noIgnore {
+		srch.SetIgnoreFiles(nil)
+	}
+	if *useGit {
+		srch.SetGit(*untracked)
+	} else if *untracked {
+		log.Fatal("-untracked requires -git")
+	}
+	if *revision != "" {
+		if *since != "" {
+			log.Fatal("-since can't be combined with -rev")
+		}
+		srch.SetRevision(*revision)
+	}
+	srch.AddRules(rules...)
 
-	nWorkers := max(1, runtime.NumCPU()-2)
-	scanRes := scanner.Scan(nWorkers, srchRes)
+	var list io.Reader
+	switch *filesFrom {
+	case "":
+	case "-":
+		list = os.Stdin
+	default:
+		f, err := os.Open(*filesFrom)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		list = f
+	}
+	sep := byte('\n')
+	if *nulSep {
+		sep = 0
+	}
+
+	// Only the files changed since the base commit are searched
+	var chg *changes
+	if *since != "" {
+		if list != nil {
+			log.Fatal("-since can't be combined with -files-from")
+		}
+		var err error
+		if chg, err = loadChanges(*since); err != nil {
+			log.Fatal(err)
+		}
+		paths, err := chg.paths(roots)
+		if err != nil {
+			log.Fatal(err)
+		}
+		list = strings.NewReader(strings.Join(paths, "\x00"))
+		sep = 0
+	}
 
-	printResults(flag.Args(), scanRes)
+	if *dryRun {
+		explain := func(path, reason string) {
+			fmt.Printf("%s\t%s\n", path, reason)
+		}
+		if list != nil {
+			if err := srch.WalkList(list, sep, explain); err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			srch.WalkPaths(roots, explain)
+		}
+		return
+	}
+
+	var srchRes <-chan searcher.Result
+	if list != nil {
+		srchRes = srch.SearchList(list, sep)
+	} else {
+		srchRes = srch.SearchPaths(roots)
+	}
+
+	aliases := cfg.aliases()
+	scanRes := scanner.Scan(nWorkers(), opts, srchRes)
+	if chg != nil {
+		scanRes = chg.filter(scanRes)
+	}
+
+	f := filter{
+		tags:     normalizeTags(tags, aliases),
+		assignee: *assignee,
+		priority: *priority,
+		due:      *listDue,
+		author:   *author,
+	}
+	if *olderThan != "" {
+		age, err := parseAge(*olderThan)
+		if err != nil {
+			log.Fatal(err)
+		}
+		f.before = time.Now().Add(-age)
+	}
+	if *showBlame || f.author != "" || !f.before.IsZero() || *sortBy != "file" {
+		b, err := newBlamer(*revision)
+		if err != nil {
+			log.Fatal(err)
+		}
+		scanRes = b.enrich(scanRes)
+	}
+
+	var nOverdue int
+	switch {
+	case tmpl != nil:
+		var err error
+		if nOverdue, err = printTemplate(os.Stdout, f, scanRes, tmpl); err != nil {
+			log.Fatal(err)
+		}
+	case *format == "json" || *format == "ndjson":
+		var err error
+		if nOverdue, err = printJSON(os.Stdout, f, scanRes, *format == "ndjson"); err != nil {
+			log.Fatal(err)
+		}
+	case *format == "grep":
+		nOverdue = printGrep(os.Stdout, f, scanRes)
+	case *format == "sarif":
+		levels, err := cfg.severities()
+		if err != nil {
+			log.Fatal(err)
+		}
+		nOverdue = printSARIF(f, scanRes, levels)
+	case *sortBy != "file":
+		nOverdue = printSorted(f, scanRes, roots, *sortBy)
+	case *listDue:
+		nOverdue = printDue(f, scanRes, roots)
+	case *groupTags:
+		nOverdue = printGrouped(f, scanRes, roots)
+	default:
+		nOverdue = printResults(f, scanRes, roots)
+	}
 
 	if *memprofile != "" {
 		startMemProfiling(*memprofile)
 	}
+
+	if *failOverdue && nOverdue > 0 {
+		fmt.Fprintf(os.Stderr, "%d overdue reminders\n", nOverdue)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: reminders [flags] [path ...] [@tag ...]\n")
+	fmt.Fprintf(out, "       reminders history|report [flags] ...\n\n")
+	fmt.Fprintf(out, "Print the reminders of the given files and directories, defaulting to the\n")
+	fmt.Fprintf(out, "current directory. Text output shows the paths of files relative to the\n")
+	fmt.Fprintf(out, "directory they were found in. The grep, json, ndjson and sarif formats show\n")
+	fmt.Fprintf(out, "them joined with the directory as given, so they can be opened from where\n")
+	fmt.Fprintf(out, "the command runs.\n\n")
+	flag.PrintDefaults()
+}
+
+// Split the command line arguments into the files and directories
+// to scan, defaulting to the current directory, and the tags to
+// print, which start with an at sign.
+func parseArgs(args []string) (roots, tags []string) {
+	tags = append(tags, tagFlags...)
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "@") {
+			tags = append(tags, arg)
+		} else {
+			roots = append(roots, arg)
+		}
+	}
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+	return roots, tags
+}
+
+// Returns a searcher for the files of all registered languages,
+// with the configured rules.
+func newSearcher(cfg config) searcher.Searcher {
+	include := make(searcher.StringSet)
+	for _, ext := range scanner.Extensions() {
+		include[ext] = true
+	}
+	srch := searcher.New(include, exclude)
+	srch.AddRules(cfg.Files...)
+	return srch
+}
+
+// scanFlagSet holds the configuration and scanning flags shared
+// by the commands.
+type scanFlagSet struct {
+	config   *string
+	docs     *bool
+	keywords *bool
+}
+
+// Define the configuration and scanning flags of a command.
+func scanFlags(fs *flag.FlagSet) scanFlagSet {
+	return scanFlagSet{
+		config:   fs.String("config", "", "read project configuration from `file` instead of "+projectConfig),
+		docs:     fs.Bool("docs", false, "scan documentation strings and blocks, like docstrings and POD"),
+		keywords: fs.Bool("keywords", false, "recognize keywords like TODO and FIXME without the @ prefix"),
+	}
+}
+
+// Load and apply the configuration, and return it with the scanner
+// options given by the flags. Exits on errors.
+func (sf scanFlagSet) load() (config, scanner.Options) {
+	cfg, err := loadConfig(*sf.config)
+	if err == nil {
+		err = cfg.apply()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := scanner.Options{
+		DocComments: *sf.docs,
+		Aliases:     cfg.aliases(),
+	}
+	if *sf.keywords {
+		if opts.Keywords, err = cfg.keywords(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	return cfg, opts
+}
+
+func nWorkers() int {
+	return max(1, runtime.NumCPU()-2)
 }
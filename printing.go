@@ -1,49 +1,324 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
 	"fmt"
+	"io"
+	"iter"
 	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/MHmorgan/reminders/reminder"
 	"github.com/MHmorgan/reminders/scanner"
 	"github.com/MHmorgan/reminders/tio"
 )
 
-// Print all the received scan results, using the given
-// tags slice as a filter.
+// A filter selects the reminders to print.
+type filter struct {
+	tags     map[string]struct{}
+	assignee string
+	priority int  // Lowest priority to print, or 0 for all
+	due      bool // Only reminders with a due date
+	author   string
+	before   time.Time // Only reminders introduced before, unless zero
+}
+
+// A tally counts the scanned files, and the reminders matching
+// a filter which are overdue.
+type tally struct {
+	files   int
+	overdue int
+}
+
+// matching returns an iterator over the received reminders which
+// match the filter, in the order received, counted in t.
+func (f *filter) matching(scanRes <-chan scanner.Result, t *tally) iter.Seq[reminder.Reminder] {
+	return func(yield func(reminder.Reminder) bool) {
+		now := time.Now()
+		for res := range scanRes {
+			for r := range res.Reminders {
+				if !f.match(r) {
+					continue
+				}
+				if r.Overdue(now) {
+					t.overdue++
+				}
+				if !yield(r) {
+					return
+				}
+			}
+			t.files++
+		}
+	}
+}
+
+// collect returns the received reminders which match the filter,
+// in the order received, and their tally.
+func (f *filter) collect(scanRes <-chan scanner.Result) ([]reminder.Reminder, tally) {
+	var t tally
+	reminders := slices.Collect(f.matching(scanRes, &t))
+	return reminders, t
+}
+
+// Print all the received scan results, using the given filter,
+// with the paths of files relative to the roots they were found in.
+// Returns the number of printed reminders which are overdue.
 func printResults(
-	tags []string,
+	f filter,
 	scanRes <-chan scanner.Result,
-) {
-	filters := normalizeTags(tags)
-	nFiles := 0
-	for res := range scanRes {
-		base := path.Base(res.Path)
-		dir := path.Dir(res.Path)
-		printPath := true
-
-		for r := range res.Reminders {
-			if !shouldPrint(r, filters) {
-				continue
+	roots []string,
+) int {
+	var t tally
+	now := time.Now()
+	file := ""
+	for r := range f.matching(scanRes, &t) {
+		if r.File() != file {
+			file = r.File()
+			printFileHeader(rootRel(roots, file))
+		}
+		printReminder(r, now)
+	}
+
+	printSummary(t.files)
+	return t.overdue
+}
+
+// Print the received scan results grouped by the top-level segment
+// of the first printed tag of each reminder, using the given filter,
+// with the paths of files relative to the roots they were found in.
+// Returns the number of printed reminders which are overdue.
+func printGrouped(
+	f filter,
+	scanRes <-chan scanner.Result,
+	roots []string,
+) int {
+	reminders, t := f.collect(scanRes)
+	var (
+		groups []string
+		byTag  = make(map[string][]reminder.Reminder)
+	)
+	for _, r := range reminders {
+		group := groupOf(r, f.tags)
+		if _, ok := byTag[group]; !ok {
+			groups = append(groups, group)
+		}
+		byTag[group] = append(byTag[group], r)
+	}
+	slices.Sort(groups)
+
+	now := time.Now()
+	for _, group := range groups {
+		reminders := byTag[group]
+		fmt.Printf("\n%s%s%s%s (%d)%s\n", tio.Bold, tio.FgCyan, group, tio.Reset+tio.Dim, len(reminders), tio.Reset)
+
+		file := ""
+		for _, r := range reminders {
+			if r.File() != file {
+				file = r.File()
+				printFileHeader(rootRel(roots, file))
 			}
-			if printPath {
-				fmt.Printf("\n%s%s%s%s/%s%s%s\n", tio.Bold, tio.Dim, dir, tio.Reset, tio.Bold, base, tio.Reset)
-				printPath = false
+			printReminder(r, now)
+		}
+	}
+
+	printSummary(t.files)
+	return t.overdue
+}
+
+// Return the top-level segment of the first of the reminder's tags
+// matching the filters.
+func groupOf(r reminder.Reminder, filters map[string]struct{}) string {
+	top, _, _ := strings.Cut(ruleTag(r, filters), "/")
+	return top
+}
+
+// Return the path of the file relative to the first of the roots
+// containing it, like main.go for src/main.go with the root src.
+// Files given as roots, and files outside the roots, keep their path.
+func rootRel(roots []string, file string) string {
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, file)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return rel
+	}
+	return file
+}
+
+func printFileHeader(file string) {
+	base := path.Base(file)
+	dir := path.Dir(file)
+	fmt.Printf("\n%s%s%s%s/%s%s%s\n", tio.Bold, tio.Dim, dir, tio.Reset, tio.Bold, base, tio.Reset)
+}
+
+// Print a single reminder line.
+func printReminder(r reminder.Reminder, now time.Time) {
+	if r.Overdue(now) {
+		fmt.Printf("%s%4d%s: %s %s(overdue)%s%s\n", tio.FgRed, r.Line(), tio.Reset, r.Format(), tio.FgRed, tio.Reset, formatOrigin(r))
+		return
+	}
+	fmt.Printf("%4d: %s%s\n", r.Line(), r.Format(), formatOrigin(r))
+}
+
+// Format the author and date of the commit which introduced the
+// reminder, if known.
+func formatOrigin(r reminder.Reminder) string {
+	o := r.Origin()
+	if o.Commit == "" {
+		return ""
+	}
+	return fmt.Sprintf(" %s(%s, %s)%s", tio.Dim, o.Author, o.Date.Format(time.DateOnly), tio.Reset)
+}
+
+// Print the reminders sorted by age, oldest first, or by author,
+// using the given filter. Reminders which are not committed are
+// newest, and have no author. Paths are relative to the roots the
+// files were found in. Returns the number of printed reminders which
+// are overdue.
+func printSorted(
+	f filter,
+	scanRes <-chan scanner.Result,
+	roots []string,
+	by string,
+) int {
+	reminders, t := f.collect(scanRes)
+
+	byAge := func(a, b reminder.Reminder) int {
+		da, db := a.Origin().Date, b.Origin().Date
+		switch {
+		case da.IsZero() || db.IsZero():
+			return cmp.Compare(boolInt(da.IsZero()), boolInt(db.IsZero()))
+		default:
+			return da.Compare(db)
+		}
+	}
+	slices.SortStableFunc(reminders, func(a, b reminder.Reminder) int {
+		if by == "author" {
+			if c := strings.Compare(strings.ToLower(a.Origin().Author), strings.ToLower(b.Origin().Author)); c != 0 {
+				return c
 			}
-			fmt.Printf("%4d: %s\n", r.Line(), r.Format())
 		}
+		return byAge(a, b)
+	})
 
-		nFiles++
+	now := time.Now()
+	if len(reminders) > 0 {
+		fmt.Println()
 	}
+	for _, r := range reminders {
+		date, author := "uncommitted", ""
+		if o := r.Origin(); o.Commit != "" {
+			date, author = o.Date.Format(time.DateOnly), o.Author
+		}
+		overdue := ""
+		if r.Overdue(now) {
+			overdue = fmt.Sprintf(" %s(overdue)%s", tio.FgRed, tio.Reset)
+		}
+		fmt.Printf("%s%-11s%s %s%s%s %s%s:%d%s: %s%s\n", tio.FgGreen, date, tio.Reset, tio.Bold, author, tio.Reset, tio.Dim, rootRel(roots, r.File()), r.Line(), tio.Reset, r.Format(), overdue)
+	}
+
+	printSummary(t.files)
+	return t.overdue
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Print the reminders sorted by due date, using the given filter,
+// which should only match reminders with a due date. Paths are
+// relative to the roots the files were found in. Returns the number
+// of printed reminders which are overdue.
+func printDue(
+	f filter,
+	scanRes <-chan scanner.Result,
+	roots []string,
+) int {
+	reminders, t := f.collect(scanRes)
+	slices.SortStableFunc(reminders, func(a, b reminder.Reminder) int {
+		return a.Attributes().Due.Compare(b.Attributes().Due)
+	})
+
+	now := time.Now()
+	if len(reminders) > 0 {
+		fmt.Println()
+	}
+	for _, r := range reminders {
+		color := tio.FgGreen
+		if r.Overdue(now) {
+			color = tio.FgRed
+		}
+		due := r.Attributes().Due.Format(time.DateOnly)
+		fmt.Printf("%s%s%s %s%s:%d%s: %s\n", color, due, tio.Reset, tio.Dim, rootRel(roots, r.File()), r.Line(), tio.Reset, r.Format())
+	}
+
+	printSummary(t.files)
+	return t.overdue
+}
+
+// Print the reminders in the compiler-style format understood by
+// editors, like vim's quickfix list, using the given filter:
+//
+//	file:line:col: tag: text
+//
+// Returns the number of printed reminders which are overdue.
+func printGrep(
+	w io.Writer,
+	f filter,
+	scanRes <-chan scanner.Result,
+) int {
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	var t tally
+	now := time.Now()
+	for r := range f.matching(scanRes, &t) {
+		tag := ruleTag(r, f.tags)
+		if tag != "" {
+			tag += ": "
+		}
+		overdue := ""
+		if r.Overdue(now) {
+			overdue = " (overdue)"
+		}
+		fmt.Fprintf(bw, "%s:%d:%d: %s%s%s\n", r.File(), r.Line(), max(r.Column(), 1), tag, messageOf(r), overdue)
+	}
+	return t.overdue
+}
 
+// Return the text of the reminder without the tags it starts with.
+func messageOf(r reminder.Reminder) string {
+	text := []rune(r.Text())
+	pos := 0
+	for _, sp := range r.Spans() {
+		for pos < len(text) && unicode.IsSpace(text[pos]) {
+			pos++
+		}
+		if sp.Start != pos {
+			break
+		}
+		pos = sp.End
+	}
+	return strings.TrimLeft(string(text[pos:]), " \t:-")
+}
+
+func printSummary(nFiles int) {
 	nLines := scanner.ScannedLines.Load()
 	fmt.Printf("\nScanned %d lines in %d files.\n", nLines, nFiles)
 }
 
-// Normalize the given tags input to a map of tags which should
-// be used when filtering reminders to print.
-func normalizeTags(tags []string) map[string]struct{} {
+// Normalize the given tags input to a map of canonical tags
+// which should be used when filtering reminders to print.
+func normalizeTags(tags []string, aliases reminder.Aliases) map[string]struct{} {
 	if len(tags) == 0 {
 		return map[string]struct{}{
 			"bug":      {},
@@ -57,7 +332,8 @@ func normalizeTags(tags []string) map[string]struct{} {
 
 	filters := make(map[string]struct{}, len(tags))
 	for _, a := range tags {
-		tag := strings.ToLower(strings.TrimSpace(a))
+		tag := reminder.NormalizeTag(strings.Trim(strings.TrimSpace(a), "@/."))
+		tag = aliases.Resolve(tag)
 		if tag == "" {
 			continue
 		}
@@ -69,6 +345,32 @@ func normalizeTags(tags []string) map[string]struct{} {
 	return filters
 }
 
+// Return true if the reminder should be printed.
+func (f *filter) match(r reminder.Reminder) bool {
+	attrs := r.Attributes()
+	if f.assignee != "" && !strings.EqualFold(attrs.Assignee, f.assignee) {
+		return false
+	}
+	if f.priority > 0 && (attrs.Priority == 0 || attrs.Priority > f.priority) {
+		return false
+	}
+	if f.due && attrs.Due.IsZero() {
+		return false
+	}
+	origin := r.Origin()
+	if f.author != "" && !containsFold(origin.Author, f.author) && !containsFold(origin.Email, f.author) {
+		return false
+	}
+	if !f.before.IsZero() && (origin.Date.IsZero() || !origin.Date.Before(f.before)) {
+		return false
+	}
+	return shouldPrint(r, f.tags)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
 // Return true if the reminder should be printed,
 // based on the given filters.
 func shouldPrint(r reminder.Reminder, filters map[string]struct{}) bool {
@@ -77,9 +379,25 @@ func shouldPrint(r reminder.Reminder, filters map[string]struct{}) bool {
 	}
 
 	for _, tag := range r.Tags() {
-		if _, ok := filters[tag]; ok {
+		if matchTag(tag, filters) {
 			return true
 		}
 	}
 	return false
 }
+
+// Return true if the tag, or any of its parents in the tag
+// hierarchy, is in the filters. The filter `bug` matches both
+// `bug/ui` and `bug/api`.
+func matchTag(tag string, filters map[string]struct{}) bool {
+	for {
+		if _, ok := filters[tag]; ok {
+			return true
+		}
+		i := strings.LastIndexByte(tag, '/')
+		if i < 0 {
+			return false
+		}
+		tag = tag[:i]
+	}
+}
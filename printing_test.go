@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+)
+
+// scanResults returns scan results of the given reminders, with
+// a result per file.
+func scanResults(reminders ...reminder.Reminder) <-chan scanner.Result {
+	out := make(chan scanner.Result, len(reminders))
+	var ch chan reminder.Reminder
+	file := ""
+	for _, r := range reminders {
+		if ch == nil || r.File() != file {
+			if ch != nil {
+				close(ch)
+			}
+			file = r.File()
+			ch = make(chan reminder.Reminder, len(reminders))
+			out <- scanner.Result{Path: file, Reminders: ch}
+		}
+		ch <- r
+	}
+	if ch != nil {
+		close(ch)
+	}
+	close(out)
+	return out
+}
+
+var messageTests = []struct {
+	name  string
+	text  string
+	spans []reminder.Span
+	msg   string
+}{
+	{name: "no tags", text: "Plain text", msg: "Plain text"},
+	{name: "leading tag", text: "todo Refactor", spans: []reminder.Span{{Start: 0, End: 4}}, msg: "Refactor"},
+	{name: "several tags", text: "todo(bob) p(1) Refactor fix", spans: []reminder.Span{{Start: 0, End: 9}, {Start: 10, End: 14}, {Start: 24, End: 27}}, msg: "Refactor fix"},
+	{name: "inner tag", text: "Clean up todo", spans: []reminder.Span{{Start: 9, End: 13}}, msg: "Clean up todo"},
+	{name: "keyword colon", text: "TODO: fix it", spans: []reminder.Span{{Start: 0, End: 4}}, msg: "fix it"},
+	{name: "dash", text: "later - someday", spans: []reminder.Span{{Start: 0, End: 5}}, msg: "someday"},
+	{name: "leading space", text: "  todo x", spans: []reminder.Span{{Start: 2, End: 6}}, msg: "x"},
+	{name: "runes", text: "todo å", spans: []reminder.Span{{Start: 0, End: 4}}, msg: "å"},
+	{name: "only tags", text: "bug/ui", spans: []reminder.Span{{Start: 0, End: 6}}, msg: ""},
+}
+
+func TestMessage(t *testing.T) {
+	for _, tt := range messageTests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := reminder.New("a.go", 1, tt.text, nil, tt.spans)
+			if msg := messageOf(r); msg != tt.msg {
+				t.Fatalf("expected message %q, got %q", tt.msg, msg)
+			}
+		})
+	}
+}
+
+var rootRelTests = []struct {
+	name  string
+	roots []string
+	file  string
+	rel   string
+}{
+	{name: "working directory", roots: []string{"."}, file: "src/a.go", rel: "src/a.go"},
+	{name: "directory", roots: []string{"src"}, file: "src/pkg/a.go", rel: "pkg/a.go"},
+	{name: "unclean root", roots: []string{"./src/"}, file: "src/a.go", rel: "a.go"},
+	{name: "first root", roots: []string{"lib", "src", "src/pkg"}, file: "src/pkg/a.go", rel: "pkg/a.go"},
+	{name: "file root", roots: []string{"src/a.go"}, file: "src/a.go", rel: "src/a.go"},
+	{name: "sibling", roots: []string{"src"}, file: "srcs/a.go", rel: "srcs/a.go"},
+	{name: "absolute", roots: []string{"/repo"}, file: "/repo/a.go", rel: "a.go"},
+	{name: "outside", roots: []string{"."}, file: "/tmp/a.go", rel: "/tmp/a.go"},
+}
+
+func TestRootRel(t *testing.T) {
+	for _, tt := range rootRelTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rel := rootRel(tt.roots, tt.file); rel != tt.rel {
+				t.Fatalf("expected %q, got %q", tt.rel, rel)
+			}
+		})
+	}
+}
+
+func TestGrep(t *testing.T) {
+	multi := reminder.New("src/a.go", 3, "fix todo(bob) Both", []string{"fix", "todo"}, []reminder.Span{{Start: 0, End: 3}, {Start: 4, End: 13}})
+	multi.SetColumn(8)
+	overdue := reminder.New("src/a.go", 5, "todo due(2020-01-01) Late", []string{"todo", "due"}, []reminder.Span{{Start: 0, End: 4}, {Start: 5, End: 20}})
+	overdue.SetColumn(4)
+	overdue.SetAttributes(reminder.Attributes{Due: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.Local)})
+	untagged := reminder.New("b.go", 1, "Plain", nil, nil)
+
+	// Without tag filters, the untagged reminder is printed too
+	var buf bytes.Buffer
+	n := printGrep(&buf, filter{}, scanResults(multi, overdue, untagged))
+	expect := []string{
+		"src/a.go:3:8: fix: Both",
+		"src/a.go:5:4: todo: Late (overdue)",
+		"b.go:1:1: Plain",
+	}
+	if got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); !slices.Equal(got, expect) {
+		t.Fatalf("expected lines\n%q\ngot\n%q", expect, got)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 overdue reminder, got %d", n)
+	}
+
+	buf.Reset()
+	f := filter{tags: map[string]struct{}{"todo": {}}}
+	printGrep(&buf, f, scanResults(multi))
+	if got, expect := buf.String(), "src/a.go:3:8: todo: Both\n"; got != expect {
+		t.Fatalf("expected the filtered tag in %q, got %q", expect, got)
+	}
+}
@@ -0,0 +1,101 @@
+package reminder
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonReminder is the JSON form of a reminder.
+type jsonReminder struct {
+	File        string      `json:"file"`
+	Line        int         `json:"line"`
+	Column      int         `json:"column,omitempty"`
+	EndLine     int         `json:"end_line"`
+	Text        string      `json:"text"`
+	Tags        []string    `json:"tags"`
+	Spans       []jsonSpan  `json:"spans"`
+	Assignee    string      `json:"assignee,omitempty"`
+	Due         string      `json:"due,omitempty"`
+	Priority    int         `json:"priority,omitempty"`
+	Issue       string      `json:"issue,omitempty"`
+	Origin      *jsonOrigin `json:"origin,omitempty"`
+	Occurrence  int         `json:"occurrence,omitempty"`
+	Fingerprint string      `json:"fingerprint"`
+}
+
+type jsonSpan struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type jsonOrigin struct {
+	Commit string    `json:"commit"`
+	Author string    `json:"author"`
+	Email  string    `json:"email"`
+	Date   time.Time `json:"date"`
+}
+
+// MarshalJSON encodes the reminder as a JSON object with its location,
+// text, tags, tag spans, the attributes which are set, its origin if
+// known, and its fingerprint.
+func (r Reminder) MarshalJSON() ([]byte, error) {
+	j := jsonReminder{
+		File:        r.file,
+		Line:        r.line,
+		Column:      r.column,
+		EndLine:     r.endLine,
+		Text:        r.text,
+		Tags:        r.tags,
+		Spans:       make([]jsonSpan, len(r.spans)),
+		Assignee:    r.attrs.Assignee,
+		Priority:    r.attrs.Priority,
+		Issue:       r.attrs.Issue,
+		Occurrence:  r.occurrence,
+		Fingerprint: r.Fingerprint(),
+	}
+	if j.Tags == nil {
+		j.Tags = []string{}
+	}
+	for i, s := range r.spans {
+		j.Spans[i] = jsonSpan(s)
+	}
+	if !r.attrs.Due.IsZero() {
+		j.Due = r.attrs.Due.Format(time.DateOnly)
+	}
+	if o := r.origin; o.Commit != "" {
+		j.Origin = &jsonOrigin{o.Commit, o.Author, o.Email, o.Date}
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes a reminder encoded by MarshalJSON.
+func (r *Reminder) UnmarshalJSON(data []byte) error {
+	var j jsonReminder
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*r = New(j.File, j.Line, j.Text, j.Tags, nil)
+	r.column = j.Column
+	r.endLine = max(j.EndLine, j.Line)
+	for _, s := range j.Spans {
+		r.spans = append(r.spans, Span(s))
+	}
+	r.attrs = Attributes{
+		Assignee: j.Assignee,
+		Priority: j.Priority,
+		Issue:    j.Issue,
+	}
+	if j.Due != "" {
+		due, err := time.ParseInLocation(time.DateOnly, j.Due, time.Local)
+		if err != nil {
+			return err
+		}
+		r.attrs.Due = due
+	}
+	if o := j.Origin; o != nil {
+		r.origin = Origin{o.Commit, o.Author, o.Email, o.Date}
+	}
+	r.occurrence = j.Occurrence
+	return nil
+}
@@ -0,0 +1,46 @@
+package reminder
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSON(t *testing.T) {
+	r := New("src/a.go", 3, "todo(alice) Refactor", []string{"todo"}, []Span{{0, 11}})
+	r.SetEndLine(4)
+	r.SetAttributes(Attributes{
+		Assignee: "alice",
+		Due:      time.Date(2026, time.December, 1, 0, 0, 0, 0, time.Local),
+		Priority: 2,
+		Issue:    "#12",
+	})
+	r.SetOrigin(Origin{
+		Commit: "ac3ab9b6364df30fd86e724de92ffa6adf4f447a",
+		Author: "Alice",
+		Email:  "alice@example.com",
+		Date:   time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC),
+	})
+	r.SetOccurrence(1)
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["due"] != "2026-12-01" || fields["fingerprint"] != r.Fingerprint() {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var decoded Reminder
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, r) {
+		t.Errorf("expected round trip of\n%+v\ngot\n%+v", r, decoded)
+	}
+}
@@ -1,7 +1,13 @@
 package reminder
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/MHmorgan/reminders/tio"
 )
@@ -9,11 +15,37 @@ import (
 // Reminder stores the location, text, tags, and formatting ranges extracted
 // from a source comment.
 type Reminder struct {
-	file  string
-	line  int
-	tags  []string
-	text  string
-	spans []Span
+	file    string
+	line    int
+	column  int
+	endLine int
+	tags    []string
+	text    string
+	spans   []Span
+	attrs   Attributes
+	origin  Origin
+	// The number of preceding reminders in the file with
+	// the same text and tags
+	occurrence int
+}
+
+// Attributes holds the structured values given as tag arguments,
+// like an assignee in the parentheses of a todo tag, a due date or
+// a priority. Zero values are unset.
+type Attributes struct {
+	Assignee string
+	Due      time.Time
+	Priority int // 1 is the highest priority
+	Issue    string
+}
+
+// Origin is the commit which introduced a reminder, as found by
+// blaming its line. The zero value is unknown or not yet committed.
+type Origin struct {
+	Commit string
+	Author string
+	Email  string
+	Date   time.Time
 }
 
 // Span marks the rune offsets of a tag within the reminder text.
@@ -23,14 +55,40 @@ type Span struct {
 	End   int
 }
 
+// NormalizeTag returns the canonical form of a tag: lowercase, with
+// `/` separating the segments of hierarchical tags like `bug/ui` and
+// `later.perf`.
+func NormalizeTag(tag string) string {
+	return strings.ReplaceAll(strings.ToLower(tag), ".", "/")
+}
+
+// Aliases maps tags to their canonical tag, like fixme to fix.
+type Aliases map[string]string
+
+// Resolve returns the canonical form of a normalized tag. An alias of
+// the top-level segment applies to the whole hierarchy, so with the
+// alias fixme to fix, fixme/ui resolves to fix/ui.
+func (a Aliases) Resolve(tag string) string {
+	if canon, ok := a[tag]; ok {
+		return canon
+	}
+	if top, rest, ok := strings.Cut(tag, "/"); ok {
+		if canon, ok := a[top]; ok {
+			return canon + "/" + rest
+		}
+	}
+	return tag
+}
+
 // New constructs a Reminder for the given file, line, text, tags, and spans.
 func New(file string, line int, text string, tags []string, spans []Span) Reminder {
 	return Reminder{
-		file:  file,
-		line:  line,
-		text:  text,
-		tags:  tags,
-		spans: spans,
+		file:    file,
+		line:    line,
+		endLine: line,
+		text:    text,
+		tags:    tags,
+		spans:   spans,
 	}
 }
 
@@ -44,6 +102,18 @@ func (r Reminder) Line() int {
 	return r.line
 }
 
+// Column reports the 1-based column of the first tag of the reminder,
+// counted in runes, or 0 if unknown.
+func (r Reminder) Column() int {
+	return r.column
+}
+
+// EndLine reports the last line of the reminder, which differs from
+// Line when the reminder continues over multiple comment lines.
+func (r Reminder) EndLine() int {
+	return r.endLine
+}
+
 // Text returns the normalized reminder text.
 func (r Reminder) Text() string {
 	return r.text
@@ -59,48 +129,102 @@ func (r Reminder) Spans() []Span {
 	return r.spans
 }
 
+// Attributes returns the structured values parsed from tag arguments.
+func (r Reminder) Attributes() Attributes {
+	return r.attrs
+}
+
+// Origin returns the commit which introduced the reminder, if known.
+func (r Reminder) Origin() Origin {
+	return r.origin
+}
+
+// Fingerprint returns an identity of the reminder which is stable
+// across scans, unlike its line, which changes when code above it is
+// edited. It is computed from the file path, the normalized text, the
+// tags and the occurrence index of the reminder.
+func (r Reminder) Fingerprint() string {
+	tags := slices.Sorted(slices.Values(r.tags))
+	text := strings.Join(strings.Fields(strings.ToLower(r.text)), " ")
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d", filepath.ToSlash(r.file), text, strings.Join(tags, " "), r.occurrence)
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// Occurrence returns the number of preceding reminders in the file
+// with the same text and tags.
+func (r Reminder) Occurrence() int {
+	return r.occurrence
+}
+
+// Overdue reports whether the reminder has a due date which has
+// passed at the given time. A reminder is due at the end of its
+// due date.
+func (r Reminder) Overdue(now time.Time) bool {
+	due := r.attrs.Due
+	return !due.IsZero() && !now.Before(due.AddDate(0, 0, 1))
+}
+
 // SetTags replaces the reminder's tags in place.
 func (r *Reminder) SetTags(tags []string) {
 	r.tags = tags
 }
 
+// SetColumn sets the column of the reminder in place.
+func (r *Reminder) SetColumn(col int) {
+	r.column = col
+}
+
+// SetEndLine sets the last line of the reminder in place.
+func (r *Reminder) SetEndLine(line int) {
+	r.endLine = line
+}
+
+// SetAttributes replaces the reminder's attributes in place.
+func (r *Reminder) SetAttributes(attrs Attributes) {
+	r.attrs = attrs
+}
+
+// SetOccurrence sets the occurrence index of the reminder in place.
+func (r *Reminder) SetOccurrence(n int) {
+	r.occurrence = n
+}
+
+// SetOrigin sets the commit which introduced the reminder in place.
+func (r *Reminder) SetOrigin(origin Origin) {
+	r.origin = origin
+}
+
+// Format returns the text of the reminder with its tags in bold.
 func (r *Reminder) Format() string {
-	spans := r.Spans()
-	if len(spans) == 0 {
-		return r.Text()
-	}
+	return r.Highlight(tio.Bold, tio.Reset, nil)
+}
 
-	text := r.Text()
-	if text == "" {
-		return text
+// Highlight returns the text of the reminder with each of its tags
+// wrapped in open and close, like <mark> and </mark>. The text is
+// passed through escape, unless nil, but open and close are not.
+func (r Reminder) Highlight(open, close string, escape func(string) string) string {
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	if len(r.spans) == 0 {
+		return escape(r.text)
 	}
 
-	runes := []rune(text)
+	runes := []rune(r.text)
 	var b strings.Builder
-	b.Grow(len(text) + len(spans)*(len(tio.Bold)+len(tio.Reset)))
-
-	spanIdx := 0
-	active := false
-	for i, rn := range runes {
-		if spanIdx < len(spans) && spans[spanIdx].Start == i {
-			if !active {
-				b.WriteString(tio.Bold)
-				active = true
-			}
-		}
-		b.WriteRune(rn)
-		if spanIdx < len(spans) && spans[spanIdx].End == i+1 {
-			if active {
-				b.WriteString(tio.Reset)
-				active = false
-			}
-			spanIdx++
+	pos := 0
+	for _, sp := range r.spans {
+		if sp.Start < pos || sp.End <= sp.Start || sp.End > len(runes) {
+			continue
 		}
+		b.WriteString(escape(string(runes[pos:sp.Start])))
+		b.WriteString(open)
+		b.WriteString(escape(string(runes[sp.Start:sp.End])))
+		b.WriteString(close)
+		pos = sp.End
 	}
-
-	if active {
-		b.WriteString(tio.Reset)
-	}
-
+	b.WriteString(escape(string(runes[pos:])))
 	return b.String()
 }
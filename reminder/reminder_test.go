@@ -0,0 +1,17 @@
+package reminder
+
+import (
+	"html"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	r := New("a.go", 1, "todo(bob) <b> p(1) fix", []string{"todo", "p"}, []Span{{0, 9}, {14, 18}})
+
+	if got, expect := r.Highlight("[", "]", nil), "[todo(bob)] <b> [p(1)] fix"; got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+	if got, expect := r.Highlight("<mark>", "</mark>", html.EscapeString), "<mark>todo(bob)</mark> &lt;b&gt; <mark>p(1)</mark> fix"; got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
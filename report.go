@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+)
+
+// A report is the reminders to render, grouped by the top-level
+// segment of their tag and then by directory.
+type report struct {
+	title  string
+	url    string // Link template, or empty for no links
+	now    time.Time
+	total  int
+	nFiles int
+	nLines uint64
+	groups []reportGroup
+}
+
+type reportGroup struct {
+	tag   string
+	count int
+	dirs  []reportDir
+}
+
+type reportDir struct {
+	dir       string
+	reminders []reminder.Reminder
+}
+
+// Run the report command, which renders the reminders into a
+// Markdown or HTML document.
+func runReport(args []string) {
+	fset := flag.NewFlagSet("report", flag.ExitOnError)
+	asHTML := fset.Bool("html", false, "render a standalone HTML document instead of Markdown")
+	output := fset.String("o", "", "write the report to `file` instead of stdout")
+	title := fset.String("title", "Reminders", "the `title` of the report")
+	linkURL := fset.String("url", "", "link reminders to `template`, like https://host/repo/blob/main/{path}#L{line}, overriding the configured report_url")
+	scanning := scanFlags(fset)
+	fset.Usage = func() {
+		fmt.Fprintf(fset.Output(), "Usage: reminders report [flags] [path ...] [@tag ...]\n\n")
+		fmt.Fprintf(fset.Output(), "Render the reminders into a Markdown or HTML document, grouped by tag\nand directory. Link templates may use {path}, {line} and {column}.\n\n")
+		fset.PrintDefaults()
+	}
+	fset.Parse(args)
+
+	cfg, opts := scanning.load()
+
+	roots, tags := parseArgs(fset.Args())
+	f := filter{tags: normalizeTags(tags, opts.Aliases)}
+	srch := newSearcher(cfg)
+
+	rep := report{
+		title: *title,
+		url:   cmp.Or(*linkURL, cfg.ReportURL),
+		now:   time.Now(),
+	}
+	reminders, t := f.collect(scanner.Scan(nWorkers(), opts, srch.SearchPaths(roots)))
+	rep.nFiles = t.files
+	rep.nLines = scanner.ScannedLines.Load()
+	rep.group(reminders, f.tags)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		w = file
+	}
+	bw := bufio.NewWriter(w)
+	if *asHTML {
+		rep.writeHTML(bw)
+	} else {
+		rep.writeMarkdown(bw)
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// group sorts the reminders into tag groups and directories, each
+// in alphabetical order, with the reminders in file and line order.
+func (rep *report) group(reminders []reminder.Reminder, filters map[string]struct{}) {
+	slices.SortStableFunc(reminders, func(a, b reminder.Reminder) int {
+		return cmp.Or(
+			cmp.Compare(reportPath(a.File()), reportPath(b.File())),
+			cmp.Compare(a.Line(), b.Line()),
+		)
+	})
+
+	byTag := make(map[string]map[string][]reminder.Reminder)
+	for _, r := range reminders {
+		tag := groupOf(r, filters)
+		if byTag[tag] == nil {
+			byTag[tag] = make(map[string][]reminder.Reminder)
+		}
+		dir := path.Dir(reportPath(r.File()))
+		byTag[tag][dir] = append(byTag[tag][dir], r)
+	}
+
+	rep.total = len(reminders)
+	rep.groups = nil
+	for _, tag := range slices.Sorted(maps.Keys(byTag)) {
+		g := reportGroup{tag: tag}
+		for _, dir := range slices.Sorted(maps.Keys(byTag[tag])) {
+			g.dirs = append(g.dirs, reportDir{dir, byTag[tag][dir]})
+			g.count += len(byTag[tag][dir])
+		}
+		rep.groups = append(rep.groups, g)
+	}
+}
+
+// link returns the URL of the reminder's location, or an empty
+// string if the report has no link template.
+func (rep *report) link(r reminder.Reminder) string {
+	if rep.url == "" {
+		return ""
+	}
+	segs := strings.Split(strings.TrimPrefix(reportPath(r.File()), "/"), "/")
+	for i, seg := range segs {
+		segs[i] = url.PathEscape(seg)
+	}
+	return strings.NewReplacer(
+		"{path}", strings.Join(segs, "/"),
+		"{line}", strconv.Itoa(r.Line()),
+		"{column}", strconv.Itoa(max(r.Column(), 1)),
+	).Replace(rep.url)
+}
+
+func (rep *report) writeMarkdown(w io.Writer) {
+	fmt.Fprintf(w, "# %s\n\n", escapeMarkdown(rep.title))
+	fmt.Fprintf(w, "%d reminders, scanned %d lines in %d files.\n", rep.total, rep.nLines, rep.nFiles)
+	if len(rep.groups) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n| Tag | Reminders |\n| --- | ---: |\n")
+	for _, g := range rep.groups {
+		fmt.Fprintf(w, "| %s | %d |\n", escapeMarkdown(g.tag), g.count)
+	}
+
+	for _, g := range rep.groups {
+		fmt.Fprintf(w, "\n## %s (%d)\n", escapeMarkdown(g.tag), g.count)
+		for _, d := range g.dirs {
+			fmt.Fprintf(w, "\n### %s (%d)\n\n", escapeMarkdown(d.dir), len(d.reminders))
+			for _, r := range d.reminders {
+				loc := fmt.Sprintf("`%s:%d`", path.Base(r.File()), r.Line())
+				if link := rep.link(r); link != "" {
+					loc = fmt.Sprintf("[%s](<%s>)", loc, link)
+				}
+				text := r.Highlight("**", "**", escapeMarkdown)
+				if r.Overdue(rep.now) {
+					text += " *(overdue)*"
+				}
+				fmt.Fprintf(w, "- %s %s\n", loc, text)
+			}
+		}
+	}
+}
+
+func (rep *report) writeHTML(w io.Writer) {
+	esc := html.EscapeString
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n%s</head>\n<body>\n", esc(rep.title), reportStyle)
+	fmt.Fprintf(w, "<h1>%s</h1>\n", esc(rep.title))
+	fmt.Fprintf(w, "<p class=\"summary\">%d reminders, scanned %d lines in %d files.</p>\n", rep.total, rep.nLines, rep.nFiles)
+
+	if len(rep.groups) > 0 {
+		fmt.Fprintf(w, "<table>\n<tr><th>Tag</th><th>Reminders</th></tr>\n")
+		for _, g := range rep.groups {
+			fmt.Fprintf(w, "<tr><td><a href=\"#tag-%s\">%s</a></td><td>%d</td></tr>\n", esc(g.tag), esc(g.tag), g.count)
+		}
+		fmt.Fprintf(w, "</table>\n")
+	}
+
+	for _, g := range rep.groups {
+		fmt.Fprintf(w, "<section>\n<h2 id=\"tag-%s\">%s <span class=\"count\">(%d)</span></h2>\n", esc(g.tag), esc(g.tag), g.count)
+		for _, d := range g.dirs {
+			fmt.Fprintf(w, "<h3>%s <span class=\"count\">(%d)</span></h3>\n<ul>\n", esc(d.dir), len(d.reminders))
+			for _, r := range d.reminders {
+				loc := fmt.Sprintf("<code>%s:%d</code>", esc(path.Base(r.File())), r.Line())
+				if link := rep.link(r); link != "" {
+					loc = fmt.Sprintf("<a href=\"%s\">%s</a>", esc(link), loc)
+				}
+				text := r.Highlight(`<mark class="tag">`, "</mark>", esc)
+				if r.Overdue(rep.now) {
+					text += ` <span class="overdue">(overdue)</span>`
+				}
+				fmt.Fprintf(w, "<li>%s %s</li>\n", loc, text)
+			}
+			fmt.Fprintf(w, "</ul>\n")
+		}
+		fmt.Fprintf(w, "</section>\n")
+	}
+	fmt.Fprintf(w, "</body>\n</html>\n")
+}
+
+const reportStyle = `<style>
+body { font-family: system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+table { border-collapse: collapse; }
+th, td { padding: 0.2rem 1rem; border-bottom: 1px solid #ddd; text-align: left; }
+td:last-child { text-align: right; }
+.count, .summary { color: #777; }
+mark.tag { background: none; color: #0a6; font-weight: bold; }
+.overdue { color: #c00; }
+ul { list-style: none; padding-left: 1rem; }
+li { margin: 0.2rem 0; }
+</style>
+`
+
+// Return the path of a file as shown in reports: slash separated,
+// without a leading ./.
+func reportPath(file string) string {
+	return filepath.ToSlash(filepath.Clean(file))
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MHmorgan/reminders/reminder"
+)
+
+func TestReportGroup(t *testing.T) {
+	reminders := []reminder.Reminder{
+		reminder.New("src/b.go", 9, "todo b9", []string{"todo"}, nil),
+		reminder.New("./src/a.go", 3, "bug/ui a3", []string{"bug/ui"}, nil),
+		reminder.New("lib/c.go", 1, "todo c1", []string{"todo"}, nil),
+		reminder.New("src/b.go", 2, "todo b2", []string{"todo"}, nil),
+		reminder.New("main.go", 4, "bug main", []string{"bug"}, nil),
+	}
+	var rep report
+	rep.group(reminders, normalizeTags(nil, nil))
+
+	if rep.total != 5 {
+		t.Errorf("expected 5 reminders, got %d", rep.total)
+	}
+	// Tag groups, each with its directories and their reminders
+	expected := []string{
+		"bug 2: . [main.go:4], src [src/a.go:3]",
+		"todo 3: lib [lib/c.go:1], src [src/b.go:2 src/b.go:9]",
+	}
+	var got []string
+	for _, g := range rep.groups {
+		var dirs []string
+		for _, d := range g.dirs {
+			var locs []string
+			for _, r := range d.reminders {
+				locs = append(locs, fmt.Sprintf("%s:%d", reportPath(r.File()), r.Line()))
+			}
+			dirs = append(dirs, d.dir+" ["+strings.Join(locs, " ")+"]")
+		}
+		got = append(got, fmt.Sprintf("%s %d: %s", g.tag, g.count, strings.Join(dirs, ", ")))
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected groups\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+var linkTests = []struct {
+	name string
+	url  string
+	file string
+	col  int
+	link string
+}{
+	{name: "none", url: "", file: "a.go"},
+	{name: "line", url: "https://host/repo/blob/main/{path}#L{line}", file: "./src/a.go", link: "https://host/repo/blob/main/src/a.go#L7"},
+	{name: "column", url: "vscode://file/{path}:{line}:{column}", file: "a.go", col: 5, link: "vscode://file/a.go:7:5"},
+	{name: "no column", url: "{path}:{column}", file: "a.go", link: "a.go:1"},
+	{name: "escaped", url: "https://host/{path}", file: "my dir/a#b.go", link: "https://host/my%20dir/a%23b.go"},
+	{name: "absolute", url: "file:///{path}", file: "/src/a.go", link: "file:///src/a.go"},
+}
+
+func TestReportLink(t *testing.T) {
+	for _, tt := range linkTests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := reminder.New(tt.file, 7, "todo", []string{"todo"}, nil)
+			if tt.col > 0 {
+				r.SetColumn(tt.col)
+			}
+			rep := report{url: tt.url}
+			if link := rep.link(r); link != tt.link {
+				t.Fatalf("expected link %q, got %q", tt.link, link)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	s := escapeMarkdown("a_b *c* [d](e) <f> `g` #h |i| \\j")
+	expected := "a\\_b \\*c\\* \\[d\\](e) \\<f\\> \\`g\\` \\#h \\|i\\| \\\\j"
+	if s != expected {
+		t.Fatalf("expected %q, got %q", expected, s)
+	}
+}
+
+// reportOf returns a report of a reminder with a highlighted tag and
+// text which must be escaped, linked to a repository.
+func reportOf(t *testing.T) *report {
+	t.Helper()
+	due := time.Date(2020, time.January, 2, 0, 0, 0, 0, time.Local)
+	r := reminder.New("src/a.go", 3, "todo Use <b> & *not* |x|", []string{"todo"}, []reminder.Span{{Start: 0, End: 4}})
+	r.SetAttributes(reminder.Attributes{Due: due})
+	rep := &report{
+		title:  "Reminders <&>",
+		url:    "https://host/{path}?a=1&l={line}",
+		now:    time.Now(),
+		nFiles: 1,
+		nLines: 10,
+	}
+	rep.group([]reminder.Reminder{r}, normalizeTags(nil, nil))
+	return rep
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	reportOf(t).writeMarkdown(&buf)
+	out := buf.String()
+
+	for _, s := range []string{
+		"# Reminders \\<&\\>\n",
+		"1 reminders, scanned 10 lines in 1 files.\n",
+		"| todo | 1 |\n",
+		"## todo (1)\n",
+		"### src (1)\n",
+		"- [`a.go:3`](<https://host/src/a.go?a=1&l=3>) **todo** Use \\<b\\> & \\*not\\* \\|x\\| *(overdue)*\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected the output to contain %q, got\n%s", s, out)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	reportOf(t).writeHTML(&buf)
+	out := buf.String()
+
+	for _, s := range []string{
+		"<title>Reminders &lt;&amp;&gt;</title>",
+		"<h1>Reminders &lt;&amp;&gt;</h1>",
+		"<tr><td><a href=\"#tag-todo\">todo</a></td><td>1</td></tr>",
+		"<h3>src <span class=\"count\">(1)</span></h3>",
+		"<li><a href=\"https://host/src/a.go?a=1&amp;l=3\"><code>a.go:3</code></a> " +
+			"<mark class=\"tag\">todo</mark> Use &lt;b&gt; &amp; *not* |x| <span class=\"overdue\">(overdue)</span></li>",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected the output to contain %q, got\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, "<b>") {
+		t.Errorf("expected the reminder text to be escaped, got\n%s", out)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// The SARIF 2.1.0 log, reduced to the properties written here.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool       sarifTool     `json:"tool"`
+		ColumnKind string        `json:"columnKind"`
+		Results    []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		Version        string      `json:"version"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string        `json:"id"`
+		ShortDescription sarifMessage  `json:"shortDescription"`
+		DefaultConfig    sarifRuleConf `json:"defaultConfiguration"`
+	}
+	sarifRuleConf struct {
+		Level string `json:"level"`
+	}
+	sarifResult struct {
+		RuleID              string            `json:"ruleId"`
+		RuleIndex           int               `json:"ruleIndex"`
+		Level               string            `json:"level"`
+		Message             sarifMessage      `json:"message"`
+		Locations           []sarifLocation   `json:"locations"`
+		PartialFingerprints map[string]string `json:"partialFingerprints"`
+		Properties          sarifProperties   `json:"properties"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysical `json:"physicalLocation"`
+	}
+	sarifPhysical struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           sarifRegion   `json:"region"`
+	}
+	sarifArtifact struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+		EndLine     int `json:"endLine"`
+	}
+	sarifProperties struct {
+		Tags []string `json:"tags"`
+	}
+)
+
+// Print the received reminders as a SARIF log, using the given filter.
+// Returns the number of printed reminders which are overdue.
+func printSARIF(
+	f filter,
+	scanRes <-chan scanner.Result,
+	levels severities,
+) int {
+	reminders, t := f.collect(scanRes)
+	if err := writeSARIF(os.Stdout, reminders, f.tags, levels); err != nil {
+		log.Fatal(err)
+	}
+	return t.overdue
+}
+
+// Write the reminders as a SARIF log. Each reminder is a result of
+// the rule named by its first tag matching the filters, with the
+// severity level of the tag.
+func writeSARIF(w io.Writer, reminders []reminder.Reminder, filters map[string]struct{}, levels severities) error {
+	results := make([]sarifResult, len(reminders))
+	for i, r := range reminders {
+		results[i] = sarifResultOf(r, filters, levels)
+	}
+
+	// One rule per tag, ordered by name
+	var ids []string
+	for _, res := range results {
+		if !slices.Contains(ids, res.RuleID) {
+			ids = append(ids, res.RuleID)
+		}
+	}
+	slices.Sort(ids)
+	rules := make([]sarifRule, len(ids))
+	for i, id := range ids {
+		rules[i] = sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{"@" + id + " reminder"},
+			DefaultConfig:    sarifRuleConf{levels.level(id)},
+		}
+	}
+	for i := range results {
+		results[i].RuleIndex, _ = slices.BinarySearch(ids, results[i].RuleID)
+	}
+
+	out := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{sarifDriver{
+				Name:           "reminders",
+				Version:        version,
+				InformationURI: "https://github.com/MHmorgan/reminders",
+				Rules:          rules,
+			}},
+			// Columns are counted in runes, not UTF-16 code units
+			ColumnKind: "unicodeCodePoints",
+			Results:    results,
+		}},
+	}
+	return writeJSON(w, out)
+}
+
+func sarifResultOf(r reminder.Reminder, filters map[string]struct{}, levels severities) sarifResult {
+	tag := ruleTag(r, filters)
+	text := r.Text()
+	if text == "" {
+		text = "@" + tag
+	}
+	return sarifResult{
+		RuleID:  tag,
+		Level:   levels.level(tag),
+		Message: sarifMessage{text},
+		Locations: []sarifLocation{{sarifPhysical{
+			ArtifactLocation: sarifArtifactOf(r.File()),
+			Region: sarifRegion{
+				StartLine:   r.Line(),
+				StartColumn: r.Column(),
+				EndLine:     r.EndLine(),
+			},
+		}}},
+		PartialFingerprints: map[string]string{"reminders/v1": r.Fingerprint()},
+		Properties:          sarifProperties{r.Tags()},
+	}
+}
+
+// Return the artifact location of a file, relative to the source root
+// unless the path is absolute.
+func sarifArtifactOf(file string) sarifArtifact {
+	if filepath.IsAbs(file) {
+		u := url.URL{Scheme: "file", Path: filepath.ToSlash(file)}
+		return sarifArtifact{URI: u.String()}
+	}
+	u := url.URL{Path: filepath.ToSlash(filepath.Clean(file))}
+	return sarifArtifact{URI: u.String(), URIBaseID: "%SRCROOT%"}
+}
+
+// Return the first of the reminder's tags matching the filters,
+// or an empty string if it has no tags.
+func ruleTag(r reminder.Reminder, filters map[string]struct{}) string {
+	tags := r.Tags()
+	for _, tag := range tags {
+		if len(filters) == 0 || matchTag(tag, filters) {
+			return tag
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	return tags[0]
+}
+
+// Write v to w as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/MHmorgan/reminders/reminder"
+)
+
+func TestSARIF(t *testing.T) {
+	bug := reminder.New("src/a.go", 3, "bug/ui Broken", []string{"bug/ui"}, nil)
+	bug.SetColumn(5)
+	later := reminder.New("b c.go", 7, "", []string{"later"}, nil)
+	mixed := reminder.New("src/a.go", 9, "fix todo Both", []string{"fix", "todo"}, nil)
+
+	cfg := config{Severities: map[string]string{"later": "none"}}
+	levels, err := cfg.severities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	filters := map[string]struct{}{"bug": {}, "later": {}, "todo": {}}
+
+	var buf bytes.Buffer
+	if err := writeSARIF(&buf, []reminder.Reminder{bug, later, mixed}, filters, levels); err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Version string
+		Runs    []struct {
+			ColumnKind string
+			Tool       struct {
+				Driver struct {
+					Rules []struct {
+						ID            string
+						DefaultConfig struct{ Level string } `json:"defaultConfiguration"`
+					}
+				}
+			}
+			Results []struct {
+				RuleID    string
+				RuleIndex int
+				Level     string
+				Message   struct{ Text string }
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI, URIBaseID string }
+						Region           struct{ StartLine, StartColumn, EndLine int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected a single SARIF 2.1.0 run, got %s", buf.Bytes())
+	}
+	run := log.Runs[0]
+	if run.ColumnKind != "unicodeCodePoints" {
+		t.Errorf("expected columns in code points, got %q", run.ColumnKind)
+	}
+
+	expect := []struct{ id, level string }{
+		{"bug/ui", "error"},
+		{"later", "none"},
+		{"todo", "note"},
+	}
+	if len(run.Results) != len(expect) {
+		t.Fatalf("expected %d results, got %d", len(expect), len(run.Results))
+	}
+	for i, res := range run.Results {
+		if res.RuleID != expect[i].id || res.Level != expect[i].level {
+			t.Errorf("result %d: expected rule %s at level %s, got %s at %s", i, expect[i].id, expect[i].level, res.RuleID, res.Level)
+		}
+		if rule := run.Tool.Driver.Rules[res.RuleIndex]; rule.ID != res.RuleID || rule.DefaultConfig.Level != res.Level {
+			t.Errorf("result %d: rule index %d names rule %+v", i, res.RuleIndex, rule)
+		}
+	}
+
+	loc := run.Results[0].Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "src/a.go" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("unexpected artifact location %+v", loc.ArtifactLocation)
+	}
+	if loc.Region.StartLine != 3 || loc.Region.StartColumn != 5 || loc.Region.EndLine != 3 {
+		t.Errorf("unexpected region %+v", loc.Region)
+	}
+	if uri := run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI; uri != "b%20c.go" {
+		t.Errorf("expected an escaped URI, got %q", uri)
+	}
+	if text := run.Results[1].Message.Text; text != "@later" {
+		t.Errorf("expected the tag as message of an empty reminder, got %q", text)
+	}
+}
@@ -0,0 +1,148 @@
+package scanner
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MHmorgan/reminders/reminder"
+)
+
+// The attribute set by the arguments of specific tags. Arguments of
+// other tags are classified by their form.
+//
+// These tags also accept a single argument after a colon,
+// like a by tag followed by `:2026-Q4`.
+var argTags = map[string]string{
+	"assignee": "assignee",
+	"owner":    "assignee",
+	"due":      "due",
+	"by":       "due",
+	"deadline": "due",
+	"p":        "priority",
+	"prio":     "priority",
+	"priority": "priority",
+	"issue":    "issue",
+	"ref":      "issue",
+	"ticket":   "issue",
+}
+
+// parseArgs sets the attributes given by the comma-separated
+// arguments of a tag. Arguments which don't parse are ignored.
+func parseArgs(attrs *reminder.Attributes, tag, args string) {
+	for arg := range strings.SplitSeq(args, ",") {
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			continue
+		}
+
+		kind, ok := argTags[tag]
+		if !ok {
+			kind = classifyArg(arg)
+		}
+
+		switch kind {
+		case "assignee":
+			attrs.Assignee = strings.TrimPrefix(arg, "@")
+		case "due":
+			if t, ok := parseDate(arg); ok {
+				attrs.Due = t
+			}
+		case "priority":
+			if p, ok := parsePriority(arg); ok {
+				attrs.Priority = p
+			}
+		case "issue":
+			attrs.Issue = arg
+		}
+	}
+}
+
+// classifyArg returns the attribute given by an argument of
+// a generic tag, like an assignee, issue, due date or priority
+// among the arguments of a todo tag.
+func classifyArg(arg string) string {
+	if _, ok := parseDate(arg); ok && strings.Contains(arg, "-") {
+		return "due"
+	}
+	if _, ok := parsePriority(arg); ok && (arg[0] == 'p' || arg[0] == 'P') {
+		return "priority"
+	}
+	if isIssue(arg) {
+		return "issue"
+	}
+	return "assignee"
+}
+
+// parseDate parses a due date like 2026-12-01. A period, like the
+// month 2026-12, the quarter 2026-Q4, the ISO week 2026-W49 or the
+// year 2026, is due on its last day.
+func parseDate(arg string) (time.Time, bool) {
+	if t, err := time.ParseInLocation(time.DateOnly, arg, time.Local); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01", arg, time.Local); err == nil {
+		return t.AddDate(0, 1, -1), true
+	}
+
+	year, period, _ := strings.Cut(arg, "-")
+	y, err := strconv.Atoi(year)
+	if err != nil || len(year) != 4 {
+		return time.Time{}, false
+	}
+
+	switch {
+	case period == "":
+		return time.Date(y, time.December, 31, 0, 0, 0, 0, time.Local), true
+	case period[0] == 'Q' || period[0] == 'q':
+		q, err := strconv.Atoi(period[1:])
+		if err != nil || q < 1 || q > 4 {
+			break
+		}
+		return time.Date(y, time.Month(3*q+1), 0, 0, 0, 0, 0, time.Local), true
+	case period[0] == 'W' || period[0] == 'w':
+		w, err := strconv.Atoi(period[1:])
+		if err != nil || w < 1 || w > 53 {
+			break
+		}
+		// Week 1 is the week containing January 4th
+		jan4 := time.Date(y, time.January, 4, 0, 0, 0, 0, time.Local)
+		monday := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7)
+		return monday.AddDate(0, 0, 7*(w-1)+6), true
+	}
+	return time.Time{}, false
+}
+
+// parsePriority parses a priority like 1 or p1.
+func parsePriority(arg string) (int, bool) {
+	arg = strings.TrimPrefix(strings.TrimPrefix(arg, "p"), "P")
+	p, err := strconv.Atoi(arg)
+	return p, err == nil && p > 0
+}
+
+// isIssue reports whether arg references an issue, like #12,
+// GH-12, PROJ-12 or a URL.
+func isIssue(arg string) bool {
+	if strings.Contains(arg, "://") {
+		return true
+	}
+
+	prefix, num, _ := strings.Cut(arg, "#")
+	if prefix != arg {
+		// Like #12 or owner/repo#12
+		_, err := strconv.Atoi(num)
+		return err == nil
+	}
+
+	prefix, num, ok := strings.Cut(arg, "-")
+	if !ok || prefix == "" {
+		return false
+	}
+	for _, c := range prefix {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	_, err := strconv.Atoi(num)
+	return err == nil
+}
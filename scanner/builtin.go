@@ -0,0 +1,173 @@
+package scanner
+
+var (
+	cComments   = []BlockComment{{Open: "/*", Close: "*/"}}
+	nestedBlock = []BlockComment{{Open: "/*", Close: "*/", Nested: true}}
+
+	dquote      = String{Open: `"`, Close: `"`, Escape: true}
+	squote      = String{Open: `'`, Close: `'`, Escape: true}
+	dquoteMulti = String{Open: `"`, Close: `"`, Escape: true, Multiline: true}
+	squoteMulti = String{Open: `'`, Close: `'`, Escape: true, Multiline: true}
+	squoteRaw   = String{Open: `'`, Close: `'`, Multiline: true}
+	char        = String{Kind: Char}
+)
+
+// Perl POD blocks start with any command paragraph and end with `=cut`.
+var pod = func() []BlockComment {
+	var blocks []BlockComment
+	for _, cmd := range []string{"=pod", "=head", "=over", "=item", "=begin", "=for", "=encoding"} {
+		blocks = append(blocks, BlockComment{Open: cmd, Close: "=cut", LineStart: true, Doc: true})
+	}
+	return blocks
+}()
+
+// The languages registered by default.
+var builtin = []Language{
+	{
+		Name:         "Shell",
+		Extensions:   []string{".bash", ".zsh"},
+		LineComments: []string{"#"},
+		Strings:      []String{dquoteMulti, squoteRaw},
+		WordStart:    true,
+	},
+	{
+		Name:          "C",
+		Extensions:    []string{".c"},
+		LineComments:  []string{"//"},
+		BlockComments: cComments,
+		Strings:       []String{dquote, char},
+	},
+	{
+		Name:          "C++",
+		Extensions:    []string{".cpp"},
+		LineComments:  []string{"//"},
+		BlockComments: cComments,
+		Strings:       []String{{Kind: CppRaw}, dquote, char},
+	},
+	{
+		Name:          "CSS",
+		Extensions:    []string{".css"},
+		BlockComments: cComments,
+		Strings:       []String{dquote, squote},
+	},
+	{
+		Name:          "Go",
+		Extensions:    []string{".go"},
+		LineComments:  []string{"//"},
+		BlockComments: cComments,
+		Strings:       []String{dquote, char, {Open: "`", Close: "`", Multiline: true}},
+	},
+	{
+		Name:          "Haskell",
+		Extensions:    []string{".hs"},
+		LineComments:  []string{"--"},
+		BlockComments: []BlockComment{{Open: "{-", Close: "-}", Nested: true}},
+		Strings:       []String{dquote, char},
+	},
+	{
+		Name:          "HTML",
+		Extensions:    []string{".html"},
+		BlockComments: []BlockComment{{Open: "<!--", Close: "-->"}},
+	},
+	{
+		Name:          "Java",
+		Extensions:    []string{".java"},
+		LineComments:  []string{"//"},
+		BlockComments: cComments,
+		Strings:       []String{{Open: `"""`, Close: `"""`, Escape: true, Multiline: true}, dquote, char},
+	},
+	{
+		Name:          "JavaScript",
+		Extensions:    []string{".js", ".ts"},
+		LineComments:  []string{"//"},
+		BlockComments: cComments,
+		Strings:       []String{dquote, squote, {Open: "`", Close: "`", Escape: true, Multiline: true}},
+	},
+	{
+		Name:          "Kotlin",
+		Extensions:    []string{".kt"},
+		LineComments:  []string{"//"},
+		BlockComments: nestedBlock,
+		Strings:       []String{{Open: `"""`, Close: `"""`, Multiline: true}, dquote, char},
+	},
+	{
+		Name:          "Lua",
+		Extensions:    []string{".lua"},
+		LineComments:  []string{"--"},
+		BlockComments: []BlockComment{{Open: "--", LongBracket: true}},
+		Strings:       []String{{Kind: LongBracket}, dquote, squote},
+	},
+	{
+		Name:         "Nushell",
+		Extensions:   []string{".nu"},
+		LineComments: []string{"#"},
+		Strings:      []String{dquote, {Open: `'`, Close: `'`}, {Open: "`", Close: "`"}},
+		WordStart:    true,
+	},
+	{
+		Name:          "OCaml",
+		Extensions:    []string{".ml", ".mli"},
+		BlockComments: []BlockComment{{Open: "(*", Close: "*)", Nested: true}},
+		Strings:       []String{dquoteMulti, char},
+	},
+	{
+		Name:          "Perl",
+		Extensions:    []string{".pl"},
+		LineComments:  []string{"#"},
+		BlockComments: pod,
+		Strings:       []String{dquoteMulti, squoteMulti},
+		WordStart:     true,
+	},
+	{
+		Name:         "Python",
+		Extensions:   []string{".py"},
+		LineComments: []string{"#"},
+		Strings: []String{
+			{Open: `"""`, Close: `"""`, Escape: true, Multiline: true, Doc: true},
+			{Open: `'''`, Close: `'''`, Escape: true, Multiline: true, Doc: true},
+			dquote,
+			squote,
+		},
+	},
+	{
+		Name:          "Ruby",
+		Extensions:    []string{".rb"},
+		LineComments:  []string{"#"},
+		BlockComments: []BlockComment{{Open: "=begin", Close: "=end", LineStart: true, Doc: true}},
+		Strings:       []String{dquoteMulti, squoteMulti},
+	},
+	{
+		Name:          "Rust",
+		Extensions:    []string{".rs"},
+		LineComments:  []string{"//"},
+		BlockComments: nestedBlock,
+		Strings:       []String{{Kind: RustRaw}, dquoteMulti, char},
+	},
+	{
+		Name:          "SQL",
+		Extensions:    []string{".sql"},
+		LineComments:  []string{"--"},
+		BlockComments: cComments,
+		Strings:       []String{squoteRaw, {Open: `"`, Close: `"`, Multiline: true}},
+	},
+	{
+		Name:          "Swift",
+		Extensions:    []string{".swift"},
+		LineComments:  []string{"//"},
+		BlockComments: nestedBlock,
+		Strings:       []String{{Open: `"""`, Close: `"""`, Escape: true, Multiline: true}, dquote},
+	},
+	{
+		Name:         "YAML",
+		Extensions:   []string{".yaml"},
+		LineComments: []string{"#"},
+		Strings:      []String{dquote, {Open: `'`, Close: `'`}},
+		WordStart:    true,
+	},
+	{
+		Name:         "Zig",
+		Extensions:   []string{".zig"},
+		LineComments: []string{"//"},
+		Strings:      []String{{Open: `\\`, Close: "\n"}, dquote, char},
+	},
+}
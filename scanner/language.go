@@ -0,0 +1,253 @@
+package scanner
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// A Language describes the comment and string literal syntax of
+// the source files with the given extensions.
+type Language struct {
+	Name          string         `json:"name"`
+	Extensions    []string       `json:"extensions"`
+	LineComments  []string       `json:"line_comments,omitempty"`
+	BlockComments []BlockComment `json:"block_comments,omitempty"`
+	Strings       []String       `json:"strings,omitempty"`
+
+	// WordStart makes line comments only start at the beginning of a
+	// word, like `#` in shell scripts where `$#` isn't a comment.
+	WordStart bool `json:"word_start,omitempty"`
+}
+
+// A BlockComment describes a comment delimited by Open and Close,
+// which may span multiple lines. Nested block comments must be
+// balanced, like `/* /* */ */` in Rust.
+//
+// With LongBracket, Open is followed by a long bracket like [[ or
+// [==[, and the comment is closed by the matching ]] or ]==],
+// like `--[[ ... ]]` in Lua. Close is ignored.
+//
+// With LineStart, the delimiters are only recognized at the very
+// start of a line, like `=begin` and `=end` in Ruby.
+//
+// Doc marks embedded documentation, like Perl POD, which is only
+// scanned for reminders with [Options.DocComments].
+type BlockComment struct {
+	Open        string `json:"open"`
+	Close       string `json:"close,omitempty"`
+	Nested      bool   `json:"nested,omitempty"`
+	LongBracket bool   `json:"long_bracket,omitempty"`
+	LineStart   bool   `json:"line_start,omitempty"`
+	Doc         bool   `json:"doc,omitempty"`
+}
+
+// A String describes a string literal. Text inside string literals
+// isn't scanned for reminders.
+//
+// Doc marks documentation strings, like Python docstrings, which are
+// scanned as multi-line comments with [Options.DocComments] when
+// they start a line. Doc only applies to quoted strings.
+type String struct {
+	Kind      StringKind `json:"kind,omitempty"`
+	Open      string     `json:"open"`
+	Close     string     `json:"close,omitempty"`
+	Escape    bool       `json:"escape,omitempty"`
+	Multiline bool       `json:"multiline,omitempty"`
+	Doc       bool       `json:"doc,omitempty"`
+}
+
+// StringKind selects how the extent of a string literal is found.
+type StringKind string
+
+const (
+	// A literal running from Open to Close, honouring backslash
+	// escapes if enabled. This is the default kind.
+	Quoted StringKind = "quoted"
+	// A character literal like 'x' or '\n'. A quote which isn't
+	// followed by a single (possibly escaped) rune and a closing
+	// quote is not a literal, leaving Rust lifetimes alone.
+	Char StringKind = "char"
+	// A Rust raw string like r"..." or r#"..."#.
+	RustRaw StringKind = "rust-raw"
+	// A C++ raw string like R"delim(...)delim".
+	CppRaw StringKind = "cpp-raw"
+	// A Lua long string like [[...]] or [==[...]==].
+	LongBracket StringKind = "long-bracket"
+)
+
+// The open delimiters of the string kinds which have a fixed one.
+var kindOpen = map[StringKind]string{
+	Char:        "'",
+	RustRaw:     "r",
+	CppRaw:      `R"`,
+	LongBracket: "[",
+}
+
+// syntax is the compiled form of a Language used by the Scanner.
+type syntax struct {
+	lineComments [][]byte
+	blocks       []blockSyntax
+	quotes       []stringSyntax
+	wordStart    bool
+
+	// Set for every byte starting a comment or string
+	first [256]bool
+}
+
+type blockSyntax struct {
+	open        []byte
+	close       []byte
+	nested      bool
+	longBracket bool
+	lineStart   bool
+	doc         bool
+}
+
+type stringSyntax struct {
+	kind      StringKind
+	open      []byte
+	close     []byte
+	escape    bool
+	multiline bool
+	doc       bool
+}
+
+func compile(lang Language) (*syntax, error) {
+	syn := new(syntax)
+	syn.wordStart = lang.WordStart
+
+	for _, prefix := range lang.LineComments {
+		if prefix == "" {
+			return nil, fmt.Errorf("language %s: empty line comment", lang.Name)
+		}
+		syn.lineComments = append(syn.lineComments, []byte(prefix))
+	}
+
+	for _, b := range lang.BlockComments {
+		if b.Open == "" || (b.Close == "" && !b.LongBracket) {
+			return nil, fmt.Errorf("language %s: block comment needs open and close", lang.Name)
+		}
+		syn.blocks = append(syn.blocks, blockSyntax{
+			open:        []byte(b.Open),
+			close:       []byte(b.Close),
+			nested:      b.Nested && !b.LongBracket,
+			longBracket: b.LongBracket,
+			lineStart:   b.LineStart,
+			doc:         b.Doc,
+		})
+	}
+
+	for _, str := range lang.Strings {
+		kind := str.Kind
+		if kind == "" {
+			kind = Quoted
+		}
+		open := str.Open
+		if o, ok := kindOpen[kind]; ok {
+			open = o
+		} else if kind != Quoted {
+			return nil, fmt.Errorf("language %s: unknown string kind %q", lang.Name, kind)
+		} else if open == "" || str.Close == "" {
+			return nil, fmt.Errorf("language %s: string needs open and close", lang.Name)
+		}
+		syn.quotes = append(syn.quotes, stringSyntax{
+			kind:      kind,
+			open:      []byte(open),
+			close:     []byte(str.Close),
+			escape:    str.Escape,
+			multiline: str.Multiline,
+			doc:       str.Doc && kind == Quoted,
+		})
+	}
+
+	// Longer delimiters must be tried before their prefixes,
+	// like `"""` before `"`.
+	longestFirst := func(a, b []byte) int { return len(b) - len(a) }
+	slices.SortStableFunc(syn.lineComments, longestFirst)
+	slices.SortStableFunc(syn.blocks, func(a, b blockSyntax) int { return longestFirst(a.open, b.open) })
+	slices.SortStableFunc(syn.quotes, func(a, b stringSyntax) int { return longestFirst(a.open, b.open) })
+
+	for _, p := range syn.lineComments {
+		syn.first[p[0]] = true
+	}
+	for _, b := range syn.blocks {
+		syn.first[b.open[0]] = true
+	}
+	for _, q := range syn.quotes {
+		syn.first[q.open[0]] = true
+	}
+	return syn, nil
+}
+
+// Compiled syntax per file extension.
+var byExtension = map[string]*syntax{}
+
+// The syntax used for files with an unknown extension, which
+// recognizes every common comment style.
+var generic = must(compile(Language{
+	Name:         "Generic",
+	LineComments: []string{"//", "#", "--"},
+	BlockComments: []BlockComment{
+		{Open: "/*", Close: "*/"},
+		{Open: "<!--", Close: "-->"},
+	},
+}))
+
+// Register adds a language to the registry, replacing any language
+// previously registered for the same extensions.
+//
+// Register must not be called while scanning.
+func Register(lang Language) error {
+	if len(lang.Extensions) == 0 {
+		return fmt.Errorf("language %s: no extensions", lang.Name)
+	}
+
+	syn, err := compile(lang)
+	if err != nil {
+		return err
+	}
+
+	for _, ext := range lang.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		byExtension[ext] = syn
+	}
+	return nil
+}
+
+// Extensions returns the sorted file extensions of all the
+// registered languages.
+func Extensions() []string {
+	exts := make([]string, 0, len(byExtension))
+	for ext := range byExtension {
+		exts = append(exts, ext)
+	}
+	slices.Sort(exts)
+	return exts
+}
+
+// syntaxFor returns the syntax of the language for the given file.
+func syntaxFor(file string) *syntax {
+	if syn, ok := byExtension[path.Ext(file)]; ok {
+		return syn
+	}
+	return generic
+}
+
+func must(syn *syntax, err error) *syntax {
+	if err != nil {
+		panic(err)
+	}
+	return syn
+}
+
+func init() {
+	for _, lang := range builtin {
+		if err := Register(lang); err != nil {
+			panic(err)
+		}
+	}
+}
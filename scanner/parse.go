@@ -0,0 +1,228 @@
+package scanner
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MHmorgan/reminders/reminder"
+)
+
+// parseComment parses the raw text of a comment into a reminder found
+// on the given line, where raw starts at the given column. The reminder
+// has no tags if the comment has none, and is located at its first tag
+// otherwise.
+func (s *Scanner) parseComment(line, col int, raw string) reminder.Reminder {
+	if raw == "" {
+		return reminder.New(s.file, line, "", nil, nil)
+	}
+
+	var (
+		tags      []string
+		lastSpace = true
+		prev      byte
+		spans     []reminder.Span
+		attrs     reminder.Attributes
+		tagAt     = -1 // Byte offset of the first tag in raw
+	)
+
+	// addTag writes the tag construct raw[start:end] to the text,
+	// unless the tag was already seen.
+	addTag := func(tag string, start, end int) {
+		tag = s.opts.Aliases.Resolve(tag)
+		if slices.Contains(tags, tag) {
+			return
+		}
+		tags = append(tags, tag)
+		tagStart := len(s.buf)
+		for _, r := range raw[start:end] {
+			s.buf = append(s.buf, r)
+		}
+		spans = append(spans, reminder.Span{
+			Start: tagStart,
+			End:   len(s.buf),
+		})
+	}
+
+	s.buf = s.buf[:0]
+	i := 0
+
+	// Consume a leading keyword
+	if word, tag := s.keyword(raw); tag != "" {
+		end := len(word)
+		if args, n := tagArgs(raw[end:]); n > 0 {
+			parseArgs(&attrs, tag, args)
+			end += n
+		}
+		addTag(tag, 0, end)
+		tagAt = 0
+		i = end
+		// Skip trailing colon
+		if i < len(raw) && raw[i] == ':' {
+			i++
+		}
+		s.buf = append(s.buf, ' ')
+		prev = ' '
+	}
+
+	for i < len(raw) {
+		c := raw[i]
+
+		switch {
+		// Normalize whitespaces into space
+		case c == '\r' || c == '\n' || c == '\t':
+			if !lastSpace && len(s.buf) > 0 {
+				s.buf = append(s.buf, ' ')
+				lastSpace = true
+			}
+			prev = ' '
+			i++
+		// Consume tags
+		case c == '@' && isTagBoundary(prev) && i+1 < len(raw) && isTagChar(raw[i+1]):
+			if !lastSpace && len(s.buf) > 0 {
+				s.buf = append(s.buf, ' ')
+			}
+
+			start := i + 1
+			j := start
+			for j < len(raw) && (isTagChar(raw[j]) || isTagSep(raw, j)) {
+				j++
+			}
+			tag := reminder.NormalizeTag(raw[start:j])
+			if args, n := tagArgs(raw[j:]); n > 0 {
+				parseArgs(&attrs, tag, args)
+				j += n
+			} else if arg, n := colonArg(tag, raw[j:]); n > 0 {
+				parseArgs(&attrs, tag, arg)
+				j += n
+			}
+			addTag(tag, start, j)
+			if tagAt < 0 {
+				tagAt = i
+			}
+			i = j
+			// Skip trailing colon
+			if i < len(raw) && raw[i] == ':' {
+				i++
+			}
+			// Keep punctuation ending a sentence next to the tag,
+			// like the period of `@by:2026-Q4.`
+			if i < len(raw) && strings.IndexByte(".,;!?", raw[i]) >= 0 {
+				for len(s.buf) > 0 && s.buf[len(s.buf)-1] == ' ' {
+					s.buf = s.buf[:len(s.buf)-1]
+				}
+				lastSpace = false
+			} else {
+				if len(s.buf) > 0 && s.buf[len(s.buf)-1] != ' ' {
+					s.buf = append(s.buf, ' ')
+				}
+				lastSpace = true
+			}
+			prev = ' '
+		// Collapse consecutive spaces
+		case c == ' ':
+			if !lastSpace && len(s.buf) > 0 {
+				s.buf = append(s.buf, ' ')
+			}
+			lastSpace = true
+			prev = ' '
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(raw[i:])
+			s.buf = append(s.buf, r)
+			lastSpace = false
+			prev = c
+			i += size
+		}
+	}
+
+	text := strings.TrimSpace(string(s.buf))
+	rem := reminder.New(s.file, line, text, tags, spans)
+	rem.SetAttributes(attrs)
+	if tagAt >= 0 {
+		rem.SetColumn(col + utf8.RuneCountInString(raw[:tagAt]))
+	}
+	return rem
+}
+
+// keyword returns the keyword starting raw, and the tag it maps to,
+// if keywords are enabled.
+func (s *Scanner) keyword(raw string) (string, string) {
+	if s.opts.Keywords == nil {
+		return "", ""
+	}
+
+	j := 0
+	for j < len(raw) && raw[j] >= 'A' && raw[j] <= 'Z' {
+		j++
+	}
+	if j == 0 || (j < len(raw) && isTagChar(raw[j])) {
+		return "", ""
+	}
+
+	word := raw[:j]
+	return word, s.opts.Keywords[word]
+}
+
+// tagArgs returns the parenthesized arguments directly following
+// a tag, like `(alice)` after a todo tag, and the length of the
+// arguments including the parentheses. The length is 0 if there
+// are no arguments.
+func tagArgs(raw string) (string, int) {
+	if raw == "" || raw[0] != '(' {
+		return "", 0
+	}
+	end := strings.IndexAny(raw, ")\n")
+	if end < 0 || raw[end] != ')' {
+		return "", 0
+	}
+	return raw[1:end], end + 1
+}
+
+// colonArg returns the argument following a colon directly after
+// a tag accepting one, like `2026-Q4` after a by tag, and the length
+// of the argument including the colon. The length is 0 if there is
+// no argument.
+func colonArg(tag, raw string) (string, int) {
+	if _, ok := argTags[tag]; !ok || len(raw) < 2 || raw[0] != ':' {
+		return "", 0
+	}
+	end := strings.IndexAny(raw, " \t\r\n")
+	if end < 0 {
+		end = len(raw)
+	}
+	// Leave punctuation ending a sentence
+	end = 1 + len(strings.TrimRight(raw[1:end], ".,;"))
+	if end == 1 {
+		return "", 0
+	}
+	return raw[1:end], end
+}
+
+func isTagBoundary(prev byte) bool {
+	if prev == 0 {
+		return true
+	}
+	return !isTagChar(prev)
+}
+
+// isTagSep reports whether raw[i] separates the segments of
+// a hierarchical tag, like `bug/ui` or `later.perf`.
+func isTagSep(raw string, i int) bool {
+	return (raw[i] == '/' || raw[i] == '.') && i+1 < len(raw) && isTagChar(raw[i+1])
+}
+
+func isTagChar(b byte) bool {
+	switch {
+	case b >= 'a' && b <= 'z':
+		return true
+	case b >= 'A' && b <= 'Z':
+		return true
+	case b >= '0' && b <= '9':
+		return true
+	case b == '_' || b == '-':
+		return true
+	default:
+		return false
+	}
+}
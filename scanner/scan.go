@@ -15,7 +15,7 @@ var ScannedLines atomic.Uint64
 //
 // For each scanned file, a single [Result] is passed
 // to the output channel.
-func Scan(nWorkers int, in <-chan searcher.Result) <-chan Result {
+func Scan(nWorkers int, opts Options, in <-chan searcher.Result) <-chan Result {
 	out := make(chan Result, nWorkers)
 
 	go func() {
@@ -27,7 +27,7 @@ func Scan(nWorkers int, in <-chan searcher.Result) <-chan Result {
 		for range nWorkers {
 			go func() {
 				defer wg.Done()
-				work(in, out)
+				work(opts, in, out)
 			}()
 		}
 
@@ -37,8 +37,9 @@ func Scan(nWorkers int, in <-chan searcher.Result) <-chan Result {
 	return out
 }
 
-func work(in <-chan searcher.Result, out chan<- Result) {
+func work(opts Options, in <-chan searcher.Result, out chan<- Result) {
 	var scn Scanner
+	scn.SetOptions(opts)
 
 	for res := range in {
 		reminders := make(chan reminder.Reminder, 1)
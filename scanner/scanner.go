@@ -7,8 +7,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"slices"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/MHmorgan/reminders/reminder"
@@ -16,12 +16,6 @@ import (
 
 const eof rune = -1
 
-var (
-	htmlOpen   = []byte("<!--")
-	htmlClose  = []byte("-->")
-	blockClose = []byte("*/")
-)
-
 // A Scanner holds the scanner's internal state while scanning
 // a source file for reminders.
 type Scanner struct {
@@ -29,17 +23,96 @@ type Scanner struct {
 	buf []rune
 
 	ch      rune
+	prev    rune
+	blank   bool // Only whitespace precedes ch on its line
 	lineNum int
+	col     int
 	file    string
+	syn     *syntax
+	opts    Options
 
 	reminders chan<- reminder.Reminder
+	pending   pending
+	// The number of emitted reminders by fingerprint, for
+	// the occurrence index of equal reminders
+	occurrences map[string]int
+}
+
+// A commentLine is a single line of a comment.
+type commentLine struct {
+	line   int
+	raw    string
+	kind   []byte // The opening delimiter of the comment
+	indent int    // Column of the opening delimiter
+	col    int    // Column of the first rune of raw
+	alone  bool   // Nothing but whitespace precedes the comment line
+}
+
+// A pending reminder, which following comment lines may continue.
+type pending struct {
+	active  bool
+	line    int
+	endLine int
+	kind    []byte
+	indent  int
+	col     int
+	raw     strings.Builder
+	rem     reminder.Reminder // Parsed from the first line
+}
+
+// Options configures optional scanner behaviour.
+type Options struct {
+	// DocComments makes the scanner scan documentation strings, like
+	// Python docstrings, and embedded documentation blocks, like Perl
+	// POD, as multi-line comments.
+	DocComments bool
+
+	// Keywords maps conventional keywords, like TODO and FIXME, to tags.
+	// A keyword is recognized without the @ prefix when it starts a
+	// comment. No keywords are recognized if nil.
+	Keywords map[string]string
+
+	// Aliases maps tags to their canonical tag, applied to all tags
+	// after normalization.
+	Aliases reminder.Aliases
+}
+
+// DefaultKeywords are the conventional keywords recognized in
+// keyword mode, and the tags they map to.
+var DefaultKeywords = map[string]string{
+	"BUG":   "bug",
+	"FIXME": "fix",
+	"HACK":  "later",
+	"TODO":  "todo",
+	"XXX":   "fix",
+}
+
+// DefaultAliases are the built-in tag aliases.
+var DefaultAliases = reminder.Aliases{
+	"bugfix": "bug",
+	"fixme":  "fix",
+	"hack":   "later",
+	"xxx":    "fix",
+}
+
+// SetOptions replaces the scanner's options.
+func (s *Scanner) SetOptions(opts Options) {
+	s.opts = opts
 }
 
 func (s *Scanner) Init(file string, rd io.Reader, out chan<- reminder.Reminder) {
 	s.ch = eof
+	s.prev = eof
 	s.lineNum = 1
+	s.col = 0
+	s.pending.active = false
 	s.file = file
+	s.syn = syntaxFor(file)
 	s.reminders = out
+	if s.occurrences == nil {
+		s.occurrences = make(map[string]int)
+	}
+	clear(s.occurrences)
 
 	if s.rd == nil {
 		s.rd = bufio.NewReaderSize(rd, 8192)
@@ -48,27 +121,23 @@ func (s *Scanner) Init(file string, rd io.Reader, out chan<- reminder.Reminder)
 	}
 }
 
+// Scan the source for comments, using the comment and string
+// syntax of the language registered for the file's extension.
 func (s *Scanner) Scan() {
 	for {
 		s.next()
 		if s.ch == eof {
+			s.flush()
 			return
 		}
+		if s.ch > 0xFF || !s.syn.first[s.ch] {
+			continue
+		}
 
-		switch {
-		case s.ch == '/' && s.peek() == '/':
-			s.next()
-			s.scanCppComment()
-		case s.ch == '/' && s.peek() == '*':
-			s.next()
-			s.scanCComment()
-		case s.ch == '#':
-			s.scanHashComment()
-		case s.ch == '-' && s.peek() == '-':
-			s.scanDashComment()
-		case s.ch == '<' && s.match(htmlOpen):
-			s.scanHtmlComment()
+		if s.scanBlockComment() || s.scanLineComment() {
+			continue
 		}
+		s.scanString()
 	}
 }
 
@@ -80,10 +149,22 @@ func (s *Scanner) next() {
 		if !errors.Is(err, io.EOF) {
 			fmt.Fprintf(os.Stderr, "Scanner error: %v", err)
 		}
+		s.prev = s.ch
 		s.ch = eof
 		return
 	}
 
+	switch {
+	case s.ch == '\n' || s.ch == eof:
+		s.blank = true
+		s.col = 1
+	case s.ch != ' ' && s.ch != '\t':
+		s.blank = false
+		s.col++
+	default:
+		s.col++
+	}
+	s.prev = s.ch
 	s.ch = r
 
 	if s.ch == '\n' {
@@ -98,6 +179,13 @@ func (s *Scanner) skip(n int) {
 	}
 }
 
+// advance moves the scanner `n` runes forward.
+func (s *Scanner) advance(n int) {
+	for range n {
+		s.next()
+	}
+}
+
 // peek returns the next rune without advancing the scanner.
 // If the scanner is at EOF, peek returns eof.
 func (s *Scanner) peek() rune {
@@ -141,55 +229,83 @@ func (s *Scanner) match(patterns ...[]byte) bool {
 	return false
 }
 
-// Scan a single-line comment starting with `//`
-func (s *Scanner) scanCppComment() {
-	line := s.lineNum
-	s.next()
-	raw := s.collectUntil(func() bool { return s.ch == '\n' })
-	s.emitReminder(line, raw)
-}
+// scanLineComment scans a single-line comment like `// ...`, if one
+// starts at the current position, and reports whether one was found.
+func (s *Scanner) scanLineComment() bool {
+	if s.syn.wordStart && !isSpace(s.prev) {
+		return false
+	}
 
-// Scan a multi-line comment like `/* ... */`
-func (s *Scanner) scanCComment() {
-	lineNum := s.lineNum
-	s.next()
-	raw := s.collectUntilPattern(blockClose)
+	for _, prefix := range s.syn.lineComments {
+		if !s.match(prefix) {
+			continue
+		}
 
-	for i, line := range strings.Split(raw, "\n") {
-		line = strings.Trim(line, " \t*")
-		s.emitReminder(lineNum+i, line)
+		c := commentLine{line: s.lineNum, kind: prefix, indent: s.col, alone: s.blank}
+		s.advance(len(prefix))
+		// Skip decorations like `///` and `---`
+		for last := rune(prefix[len(prefix)-1]); s.ch == last; {
+			s.next()
+		}
+		c.col = s.col
+		c.raw = s.collectUntil(func() bool { return s.ch == '\n' })
+		s.emitLine(c)
+		return true
 	}
-
+	return false
 }
 
-// Scan a single-line comment starting with `#`
-func (s *Scanner) scanHashComment() {
-	line := s.lineNum
-	s.next()
-	raw := s.collectUntil(func() bool { return s.ch == '\n' })
-	s.emitReminder(line, raw)
-}
+// scanBlockComment scans a multi-line comment like `/* ... */`, if one
+// starts at the current position, and reports whether one was found.
+func (s *Scanner) scanBlockComment() bool {
+	for i := range s.syn.blocks {
+		b := &s.syn.blocks[i]
+		if !s.match(b.open) || (b.lineStart && !s.atLineStart()) {
+			continue
+		}
 
-// Scan a single-line comment starting with `--`
-func (s *Scanner) scanDashComment() {
-	line := s.lineNum
-	s.next()
-	if s.ch == '-' {
-		s.next()
+		n := len(b.open)
+		if b.longBracket {
+			// The bracket level varies, so the closing delimiter
+			// is specialised for each comment.
+			p, _ := s.rd.Peek(n + 64)
+			if len(p) < n || p[n-1] != '[' {
+				continue
+			}
+			close := longBracketAfter(p[n:])
+			if close == nil {
+				continue
+			}
+			b = &blockSyntax{open: b.open, close: close}
+			n += len(close)
+		}
+
+		c := commentLine{line: s.lineNum, kind: b.open, indent: s.col, alone: s.blank}
+		s.advance(n)
+		c.col = s.col
+		c.raw = s.collectBlock(b)
+
+		if !b.doc || s.opts.DocComments {
+			s.emitBlock(c)
+		}
+		return true
 	}
-	raw := s.collectUntil(func() bool { return s.ch == '\n' })
-	s.emitReminder(line, raw)
+	return false
 }
 
-// Scan a multi-line comment like `<!-- ... -->`
-func (s *Scanner) scanHtmlComment() {
-	lineNum := s.lineNum
-	s.skip(3)
-	raw := s.collectUntilPattern(htmlClose)
+// scanDocString scans a documentation string as a multi-line comment.
+func (s *Scanner) scanDocString(q *stringSyntax) {
+	c := commentLine{line: s.lineNum, kind: q.open, indent: s.col, alone: true}
+	s.advance(len(q.open))
+	c.col = s.col
+	c.raw = s.collectBlock(&blockSyntax{open: q.open, close: q.close})
+	s.emitBlock(c)
+}
 
-	for i, line := range strings.Split(raw, "\n") {
-		s.emitReminder(lineNum+i, line)
-	}
+// atLineStart reports whether the current rune is the first one
+// on its line.
+func (s *Scanner) atLineStart() bool {
+	return s.prev == '\n' || s.prev == eof
 }
 
 func (s *Scanner) collectUntil(stop func() bool) string {
@@ -211,20 +327,28 @@ func (s *Scanner) collectUntil(stop func() bool) string {
 	return b.String()
 }
 
-func (s *Scanner) collectUntilPattern(pattern []byte) string {
-	if len(pattern) == 0 {
-		return ""
-	}
-
+// collectBlock collects the body of a block comment, leaving the last
+// rune of the closing delimiter as current. The delimiters of nested
+// block comments are replaced by spaces in the body.
+func (s *Scanner) collectBlock(blk *blockSyntax) string {
 	var b strings.Builder
+	depth := 1
+
 	for s.ch != eof {
-		if s.match(pattern) {
-			s.skip(len(pattern) - 1)
-			break
+		if s.match(blk.close) && (!blk.lineStart || s.atLineStart()) {
+			if depth--; depth == 0 {
+				s.advance(len(blk.close) - 1)
+				break
+			}
+			b.WriteByte(' ')
+			s.advance(len(blk.close))
+			continue
 		}
-
-		if s.ch == eof {
-			break
+		if blk.nested && s.match(blk.open) {
+			depth++
+			b.WriteByte(' ')
+			s.advance(len(blk.open))
+			continue
 		}
 
 		b.WriteRune(s.ch)
@@ -234,115 +358,77 @@ func (s *Scanner) collectUntilPattern(pattern []byte) string {
 	return b.String()
 }
 
-func (s *Scanner) emitReminder(line int, raw string) {
-	text, tags, spans := s.parseComment(strings.TrimSpace(raw))
-	if len(tags) == 0 {
-		return
+// emitBlock emits the reminders of each line in the body of
+// a multi-line comment.
+func (s *Scanner) emitBlock(c commentLine) {
+	for i, line := range strings.Split(c.raw, "\n") {
+		if i > 0 {
+			c.line++
+			c.col = 1
+			c.alone = true
+		}
+		trimmed := strings.TrimLeft(line, " \t*")
+		c.col += utf8.RuneCountInString(line[:len(line)-len(trimmed)])
+		c.raw = strings.TrimRight(trimmed, " \t*")
+		s.emitLine(c)
 	}
-
-	rem := reminder.New(s.file, line, text, tags, spans)
-	s.reminders <- rem
 }
 
-func (s *Scanner) parseComment(raw string) (string, []string, []reminder.Span) {
-	if raw == "" {
-		return "", nil, nil
-	}
-
-	var (
-		tags      []string
-		lastSpace = true
-		prev      byte
-		spans     []reminder.Span
-	)
-
-	s.buf = s.buf[:0]
-	for i := 0; i < len(raw); {
-		c := raw[i]
-
-		switch {
-		// Normalize whitespaces into space
-		case c == '\r' || c == '\n' || c == '\t':
-			if !lastSpace && len(s.buf) > 0 {
-				s.buf = append(s.buf, ' ')
-				lastSpace = true
-			}
-			prev = ' '
-			i++
-		// Consume tags
-		case c == '@' && isTagBoundary(prev) && i+1 < len(raw) && isTagChar(raw[i+1]):
-			if !lastSpace && len(s.buf) > 0 {
-				s.buf = append(s.buf, ' ')
-			}
-
-			start := i + 1
-			j := start
-			for j < len(raw) && isTagChar(raw[j]) {
-				j++
-			}
-			tag := raw[start:j]
-			if tag != "" && !slices.Contains(tags, tag) {
-				// Normalize tags to lowercase
-				lower := strings.ToLower(tag)
-				tags = append(tags, lower)
-				tagStart := len(s.buf)
-				for _, r := range tag {
-					s.buf = append(s.buf, r)
-				}
-				spans = append(spans, reminder.Span{
-					Start: tagStart,
-					End:   len(s.buf),
-				})
-			}
-			i = j
-			// Skip trailing colon
-			if i < len(raw) && raw[i] == ':' {
-				i++
-			}
-			if len(s.buf) > 0 {
-				s.buf = append(s.buf, ' ')
-			}
-			lastSpace = true
-			prev = ' '
-		// Collapse consecutive spaces
-		case c == ' ':
-			if !lastSpace && len(s.buf) > 0 {
-				s.buf = append(s.buf, ' ')
-			}
-			lastSpace = true
-			prev = ' '
-			i++
-		default:
-			r, size := utf8.DecodeRuneInString(raw[i:])
-			s.buf = append(s.buf, r)
-			lastSpace = false
-			prev = c
-			i += size
+// emitLine handles a single comment line, which either starts
+// a new reminder, continues the pending one, or ends it.
+//
+// A line without tags continues the pending reminder when it's
+// the next line, of the same kind and indentation, and isn't
+// preceded by code.
+func (s *Scanner) emitLine(c commentLine) {
+	raw := strings.TrimLeftFunc(c.raw, unicode.IsSpace)
+	c.col += utf8.RuneCountInString(c.raw[:len(c.raw)-len(raw)])
+	rem := s.parseComment(c.line, c.col, strings.TrimRightFunc(raw, unicode.IsSpace))
+	p := &s.pending
+
+	if len(rem.Tags()) == 0 {
+		if p.active && rem.Text() != "" && c.alone && c.line == p.endLine+1 &&
+			c.indent == p.indent && bytes.Equal(c.kind, p.kind) {
+			p.raw.WriteByte('\n')
+			p.raw.WriteString(c.raw)
+			p.endLine = c.line
+			return
 		}
+		s.flush()
+		return
 	}
 
-	text := strings.TrimSpace(string(s.buf))
-	return text, tags, spans
+	s.flush()
+	p.active = true
+	p.line = c.line
+	p.endLine = c.line
+	p.kind = c.kind
+	p.indent = c.indent
+	p.col = c.col
+	p.raw.Reset()
+	p.raw.WriteString(c.raw)
+	p.rem = rem
 }
 
-func isTagBoundary(prev byte) bool {
-	if prev == 0 {
-		return true
+// flush emits the pending reminder, if any.
+func (s *Scanner) flush() {
+	p := &s.pending
+	if !p.active {
+		return
 	}
-	return !isTagChar(prev)
-}
+	p.active = false
 
-func isTagChar(b byte) bool {
-	switch {
-	case b >= 'a' && b <= 'z':
-		return true
-	case b >= 'A' && b <= 'Z':
-		return true
-	case b >= '0' && b <= '9':
-		return true
-	case b == '_' || b == '-':
-		return true
-	default:
-		return false
+	rem := p.rem
+	if p.endLine > p.line {
+		rem = s.parseComment(p.line, p.col, strings.TrimSpace(p.raw.String()))
+		rem.SetEndLine(p.endLine)
 	}
+	fp := rem.Fingerprint()
+	rem.SetOccurrence(s.occurrences[fp])
+	s.occurrences[fp]++
+	s.reminders <- rem
+}
+
+func isSpace(r rune) bool {
+	return r == eof || r == ' ' || r == '\t' || r == '\n' || r == '\r'
 }
@@ -4,19 +4,20 @@ import (
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/MHmorgan/reminders/reminder"
 )
 
-const fTest = "langly-falls.go"
+const fTest = "langly-falls"
 
-func testScanner(t *testing.T, source string, sz int) (*Scanner, <-chan reminder.Reminder, error) {
+func testScanner(t *testing.T, file, source string, sz int) (*Scanner, <-chan reminder.Reminder, error) {
 	t.Helper()
 
 	out := make(chan reminder.Reminder, sz)
 
 	var scn Scanner
-	scn.Init(fTest, strings.NewReader(source), out)
+	scn.Init(file, strings.NewReader(source), out)
 	return &scn, out, nil
 }
 
@@ -48,18 +49,19 @@ func TestBasics(t *testing.T) {
 
 	var tests = []struct {
 		name   string
+		file   string
 		source string
 	}{
-		{name: "cpp", source: "// " + fTmpl},
-		{name: "hash", source: "# " + fTmpl},
-		{name: "dash", source: "-- " + fTmpl},
-		{name: "c", source: "/* " + fTmpl + " */"},
-		{name: "html", source: "<!-- " + fTmpl + " -->"},
+		{name: "cpp", file: fTest + ".go", source: "// " + fTmpl},
+		{name: "hash", file: fTest + ".py", source: "# " + fTmpl},
+		{name: "dash", file: fTest + ".lua", source: "-- " + fTmpl},
+		{name: "c", file: fTest + ".c", source: "/* " + fTmpl + " */"},
+		{name: "html", file: fTest + ".html", source: "<!-- " + fTmpl + " -->"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			scn, out, err := testScanner(t, tt.source, 1)
+			scn, out, err := testScanner(t, tt.file, tt.source, 1)
 			if err != nil {
 				t.Fatalf("NewScanner error: %v", err)
 			}
@@ -72,8 +74,8 @@ func TestBasics(t *testing.T) {
 			}
 
 			r := results[0]
-			if r.File() != fTest {
-				t.Fatalf("expected file %s, got %s", fTest, r.File())
+			if r.File() != tt.file {
+				t.Fatalf("expected file %s, got %s", tt.file, r.File())
 			}
 			if r.Line() != fLine {
 				t.Fatalf("expected line %d, got %d", fLine, r.Line())
@@ -123,7 +125,7 @@ var expect = []struct {
 
 func TestComposite(t *testing.T) {
 	nExp := len(expect)
-	scn, out, err := testScanner(t, compositeSource, nExp)
+	scn, out, err := testScanner(t, fTest, compositeSource, nExp)
 	if err != nil {
 		t.Fatalf("NewScanner error: %v", err)
 	}
@@ -155,3 +157,758 @@ func TestComposite(t *testing.T) {
 		}
 	}
 }
+
+// -----------------------------------------------------------------------------
+//
+// String literal tests
+//
+// -----------------------------------------------------------------------------
+
+// scanSource scans the given source as if read from file, with the
+// given options, returning all the emitted reminders.
+func scanSource(t *testing.T, file, source string, opts Options) []reminder.Reminder {
+	t.Helper()
+
+	out := make(chan reminder.Reminder)
+
+	var scn Scanner
+	scn.SetOptions(opts)
+	scn.Init(file, strings.NewReader(source), out)
+	go func() {
+		defer close(out)
+		scn.Scan()
+	}()
+
+	var results []reminder.Reminder
+	for r := range out {
+		results = append(results, r)
+	}
+	return results
+}
+
+var stringTests = []struct {
+	name   string
+	file   string
+	source string
+	lines  []int
+}{
+	{name: "go url", file: "a.go", source: `url := "http://x/@todo"`},
+	{name: "go escaped quote", file: "a.go", source: `s := "\"// @todo"`},
+	{name: "go raw", file: "a.go", source: "s := `\n// @todo\n`\n// @todo real", lines: []int{4}},
+	{name: "go rune", file: "a.go", source: `c := '"' // @todo real`, lines: []int{1}},
+	{name: "go escaped rune", file: "a.go", source: `c := '\'' // @todo real`, lines: []int{1}},
+	{name: "c printf", file: "a.c", source: `printf("# @fix");`},
+	{name: "c trailing", file: "a.c", source: `puts("/* @fix */"); /* @fix real */`, lines: []int{1}},
+	{name: "cpp raw", file: "a.cpp", source: "s = R\"x(\n)\" // @todo\n)x\";\n// @todo real", lines: []int{4}},
+	{name: "js template", file: "a.js", source: "s = `\n// @todo ${x}\n`"},
+	{name: "js single", file: "a.js", source: `s = '-- @todo'`},
+	{name: "python dquote", file: "a.py", source: `s = "# @todo"`},
+	{name: "python squote", file: "a.py", source: `s = '# @todo' # @todo real`, lines: []int{1}},
+	{name: "python triple", file: "a.py", source: "s = \"\"\"\n# @todo \"\n\"\"\"\n# @todo real", lines: []int{4}},
+	{name: "python single triple", file: "a.py", source: "s = '''\n# @todo\n'''"},
+	{name: "rust raw", file: "a.rs", source: `s = r#"// "@todo"#; // @todo real`, lines: []int{1}},
+	{name: "rust lifetime", file: "a.rs", source: "fn f<'a>(x: &'a str) {} // @todo real", lines: []int{1}},
+	{name: "lua long string", file: "a.lua", source: "s = [==[\n-- @todo ]]\n]==] -- @todo real", lines: []int{3}},
+	{name: "sql", file: "a.sql", source: `SELECT '-- @todo' FROM t; -- @todo real`, lines: []int{1}},
+	{name: "yaml squote", file: "a.yaml", source: "s: 'it''s # not a comment @todo'\n# @todo real", lines: []int{2}},
+	{name: "shell", file: "a.bash", source: `echo '# @todo \' # @todo real`, lines: []int{1}},
+	{name: "zig multiline", file: "a.zig", source: "s = \\\\ // @todo\n// @todo real", lines: []int{2}},
+}
+
+func TestStrings(t *testing.T) {
+	for _, tt := range stringTests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := scanSource(t, tt.file, tt.source, Options{})
+			var lines []int
+			for _, r := range results {
+				lines = append(lines, r.Line())
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Fatalf("expected reminders on lines %v, got %v", tt.lines, lines)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Language tests
+//
+// -----------------------------------------------------------------------------
+
+var languageTests = []struct {
+	name   string
+	file   string
+	source string
+	lines  []int
+}{
+	{name: "go hash", file: "a.go", source: "x := 1 # @todo\n// @todo real", lines: []int{2}},
+	{name: "c decrement", file: "a.c", source: "i--; // @todo real\nj-- @todo", lines: []int{1}},
+	{name: "js decrement", file: "a.js", source: "i-- /* @todo real */", lines: []int{1}},
+	{name: "python dash", file: "a.py", source: "x = y -- z  # @todo real\n-- @todo", lines: []int{1}},
+	{name: "python slashes", file: "a.py", source: "x = 1 // 2 @todo"},
+	{name: "shell count", file: "a.bash", source: "echo $# ${#a} # @todo real", lines: []int{1}},
+	{name: "yaml anchor", file: "a.yaml", source: "url: http://x#@todo\n# @todo real", lines: []int{2}},
+	{name: "html slashes", file: "a.html", source: "<a href=\"//x\">@todo</a>"},
+	{name: "rust nested", file: "a.rs", source: "/* /* */ @todo real */", lines: []int{1}},
+	{name: "c not nested", file: "a.c", source: "/* /* */ @todo */"},
+	{name: "lua doc", file: "a.lua", source: "--- @todo real", lines: []int{1}},
+	{name: "generic", file: "Makefile", source: "# @todo real\n// @todo real", lines: []int{1, 2}},
+}
+
+func TestLanguages(t *testing.T) {
+	for _, tt := range languageTests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := scanSource(t, tt.file, tt.source, Options{})
+			var lines []int
+			for _, r := range results {
+				lines = append(lines, r.Line())
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Fatalf("expected reminders on lines %v, got %v", tt.lines, lines)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	err := Register(Language{
+		Name:          "Test",
+		Extensions:    []string{"tst"},
+		LineComments:  []string{";;"},
+		BlockComments: []BlockComment{{Open: "#|", Close: "|#", Nested: true}},
+		Strings:       []String{{Open: `"`, Close: `"`, Escape: true}},
+	})
+	if err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	if !slices.Contains(Extensions(), ".tst") {
+		t.Fatalf("expected .tst in %v", Extensions())
+	}
+
+	source := "(a \";; @todo\") ;; @todo one\n#| #| |# @fix two |#"
+	results := scanSource(t, "a.tst", source, Options{})
+	if len(results) != 2 {
+		t.Fatalf("expected 2 reminders, got %d", len(results))
+	}
+	if results[0].Text() != "todo one" || results[1].Text() != "fix two" {
+		t.Fatalf("unexpected reminders %q, %q", results[0].Text(), results[1].Text())
+	}
+
+	if err := Register(Language{Name: "Bad", Extensions: []string{".bad"}, Strings: []String{{Kind: "nope"}}}); err == nil {
+		t.Fatalf("expected error for unknown string kind")
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Block comment tests
+//
+// -----------------------------------------------------------------------------
+
+var blockTests = []struct {
+	name   string
+	file   string
+	source string
+	lines  []int
+	texts  []string
+}{
+	{
+		name:   "rust",
+		file:   "a.rs",
+		source: "/* outer /* inner */\n @todo still outer */ // @fix after",
+		lines:  []int{2, 2},
+		texts:  []string{"todo still outer", "fix after"},
+	},
+	{
+		name:   "swift",
+		file:   "a.swift",
+		source: "/*\n/* @todo inner */\n@later outer\n*/ let x = 1 // @fix",
+		lines:  []int{2, 3, 4},
+		texts:  []string{"todo inner", "later outer", "fix"},
+	},
+	{
+		name:   "haskell",
+		file:   "a.hs",
+		source: "{- {- -}\n @todo nested -} x = 1 -- @fix line",
+		lines:  []int{2, 2},
+		texts:  []string{"todo nested", "fix line"},
+	},
+	{
+		name:   "ocaml",
+		file:   "a.ml",
+		source: "(* (* *)\n @todo nested *) let c = '*' (* @fix *)",
+		lines:  []int{2, 2},
+		texts:  []string{"todo nested", "fix"},
+	},
+	{
+		name:   "lua long comment",
+		file:   "a.lua",
+		source: "--[[\n@todo first\n@fix second\n]] x = 1 -- @later line",
+		lines:  []int{2, 3, 4},
+		texts:  []string{"todo first", "fix second", "later line"},
+	},
+	{
+		name:   "lua leveled comment",
+		file:   "a.lua",
+		source: "--[==[ ]] @todo still inside\n]==] -- @fix",
+		lines:  []int{1, 2},
+		texts:  []string{"]] todo still inside", "fix"},
+	},
+	{
+		name:   "lua dash bracket",
+		file:   "a.lua",
+		source: "-- [[ @todo line only\n@fix not comment",
+		lines:  []int{1},
+		texts:  []string{"[[ todo line only"},
+	},
+}
+
+func TestBlockComments(t *testing.T) {
+	for _, tt := range blockTests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := scanSource(t, tt.file, tt.source, Options{})
+			var (
+				lines []int
+				texts []string
+			)
+			for _, r := range results {
+				lines = append(lines, r.Line())
+				texts = append(texts, r.Text())
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Fatalf("expected reminders on lines %v, got %v", tt.lines, lines)
+			}
+			if !slices.Equal(texts, tt.texts) {
+				t.Fatalf("expected texts %q, got %q", tt.texts, texts)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Documentation tests
+//
+// -----------------------------------------------------------------------------
+
+const pythonDocSource = `def foo():
+    """Return foo.
+
+    @todo Handle bar
+    """
+    s = """
+    @fix not a docstring
+    """
+    '''@later single quoted '''
+`
+
+const rubyDocSource = `=begin
+@todo Ruby docs
+ =end
+@fix still docs
+=end
+x = 1 # @later code
+`
+
+const perlDocSource = `my $x = 1; # @todo code
+
+=head1 NAME
+
+@fix POD # not a comment
+
+=cut
+
+print $#x;
+`
+
+var docTests = []struct {
+	name   string
+	file   string
+	source string
+	docs   bool
+	lines  []int
+	texts  []string
+}{
+	{name: "python off", file: "a.py", source: pythonDocSource},
+	{name: "python on", file: "a.py", source: pythonDocSource, docs: true, lines: []int{4, 9}, texts: []string{"todo Handle bar", "later single quoted"}},
+	{name: "ruby off", file: "a.rb", source: rubyDocSource, lines: []int{6}, texts: []string{"later code"}},
+	{name: "ruby on", file: "a.rb", source: rubyDocSource, docs: true, lines: []int{2, 4, 6}, texts: []string{"todo Ruby docs =end", "fix still docs", "later code"}},
+	{name: "perl off", file: "a.pl", source: perlDocSource, lines: []int{1}, texts: []string{"todo code"}},
+	{name: "perl on", file: "a.pl", source: perlDocSource, docs: true, lines: []int{1, 5}, texts: []string{"todo code", "fix POD # not a comment"}},
+}
+
+func TestDocComments(t *testing.T) {
+	for _, tt := range docTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				lines []int
+				texts []string
+			)
+			for _, r := range scanSource(t, tt.file, tt.source, Options{DocComments: tt.docs}) {
+				lines = append(lines, r.Line())
+				texts = append(texts, r.Text())
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Fatalf("expected reminders on lines %v, got %v", tt.lines, lines)
+			}
+			if !slices.Equal(texts, tt.texts) {
+				t.Fatalf("expected texts %q, got %q", tt.texts, texts)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Continuation tests
+//
+// -----------------------------------------------------------------------------
+
+type span struct{ line, endLine int }
+
+var continuationTests = []struct {
+	name   string
+	file   string
+	source string
+	spans  []span
+	texts  []string
+}{
+	{
+		name:   "cpp",
+		file:   "a.go",
+		source: "// @todo refactor this because\n// the cache invalidation is wrong\nfunc x() {}",
+		spans:  []span{{1, 2}},
+		texts:  []string{"todo refactor this because the cache invalidation is wrong"},
+	},
+	{
+		name:   "new tag",
+		file:   "a.go",
+		source: "// @todo a\n// @fix b\n// c",
+		spans:  []span{{1, 1}, {2, 3}},
+		texts:  []string{"todo a", "fix b c"},
+	},
+	{
+		name:   "indentation",
+		file:   "a.go",
+		source: "// @todo a\n\t// b",
+		spans:  []span{{1, 1}},
+		texts:  []string{"todo a"},
+	},
+	{
+		name:   "trailing",
+		file:   "a.go",
+		source: "x := 1 // @todo a\n       // b\ny := 2 // c",
+		spans:  []span{{1, 2}},
+		texts:  []string{"todo a b"},
+	},
+	{
+		name:   "gap",
+		file:   "a.go",
+		source: "// @todo a\n\n// b",
+		spans:  []span{{1, 1}},
+		texts:  []string{"todo a"},
+	},
+	{
+		name:   "empty comment",
+		file:   "a.go",
+		source: "// @todo a\n//\n// b",
+		spans:  []span{{1, 1}},
+		texts:  []string{"todo a"},
+	},
+	{
+		name:   "kind",
+		file:   "Makefile",
+		source: "// @todo a\n# b",
+		spans:  []span{{1, 1}},
+		texts:  []string{"todo a"},
+	},
+	{
+		name:   "block",
+		file:   "a.c",
+		source: "/*\n * @todo a\n * b\n *\n * c\n */",
+		spans:  []span{{2, 3}},
+		texts:  []string{"todo a b"},
+	},
+	{
+		name:   "html",
+		file:   "a.html",
+		source: "<!-- @todo a\n     b -->",
+		spans:  []span{{1, 2}},
+		texts:  []string{"todo a b"},
+	},
+	{
+		name:   "hash",
+		file:   "a.py",
+		source: "# @todo a\n# b\nx = 1\n# c",
+		spans:  []span{{1, 2}},
+		texts:  []string{"todo a b"},
+	},
+	{
+		name:   "dash",
+		file:   "a.sql",
+		source: "-- @todo a\n-- b\n-- @later c",
+		spans:  []span{{1, 2}, {3, 3}},
+		texts:  []string{"todo a b", "later c"},
+	},
+}
+
+func TestContinuation(t *testing.T) {
+	for _, tt := range continuationTests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := scanSource(t, tt.file, tt.source, Options{})
+			var (
+				spans []span
+				texts []string
+			)
+			for _, r := range results {
+				spans = append(spans, span{r.Line(), r.EndLine()})
+				texts = append(texts, r.Text())
+			}
+			if !slices.Equal(spans, tt.spans) {
+				t.Fatalf("expected line spans %v, got %v", tt.spans, spans)
+			}
+			if !slices.Equal(texts, tt.texts) {
+				t.Fatalf("expected texts %q, got %q", tt.texts, texts)
+			}
+		})
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Keyword tests
+//
+// -----------------------------------------------------------------------------
+
+var keywordTests = []struct {
+	name   string
+	source string
+	tags   [][]string
+	texts  []string
+	spans  [][]reminder.Span
+}{
+	{
+		name:   "todo",
+		source: "// TODO: Clean up",
+		tags:   [][]string{{"todo"}},
+		texts:  []string{"TODO Clean up"},
+		spans:  [][]reminder.Span{{{Start: 0, End: 4}}},
+	},
+	{
+		name:   "fixme assignee",
+		source: "// FIXME(bob): Broken",
+		tags:   [][]string{{"fix"}},
+		texts:  []string{"FIXME(bob) Broken"},
+		spans:  [][]reminder.Span{{{Start: 0, End: 10}}},
+	},
+	{
+		name:   "with tags",
+		source: "/* XXX @later Slow */",
+		tags:   [][]string{{"fix", "later"}},
+		texts:  []string{"XXX later Slow"},
+		spans:  [][]reminder.Span{{{Start: 0, End: 3}, {Start: 4, End: 9}}},
+	},
+	{
+		name:   "duplicate",
+		source: "// HACK @Later @later",
+		tags:   [][]string{{"later"}},
+		texts:  []string{"HACK"},
+		spans:  [][]reminder.Span{{{Start: 0, End: 4}}},
+	},
+	{name: "not at start", source: "// Remove the TODO below"},
+	{name: "lowercase", source: "// todo: not a keyword"},
+	{name: "longer word", source: "// TODOS are fine"},
+	{name: "unknown", source: "// NOTE: nothing"},
+}
+
+func TestKeywords(t *testing.T) {
+	for _, tt := range keywordTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				tags  [][]string
+				texts []string
+				spans [][]reminder.Span
+			)
+			for _, r := range scanSource(t, "a.go", tt.source, Options{Keywords: DefaultKeywords}) {
+				tags = append(tags, r.Tags())
+				texts = append(texts, r.Text())
+				spans = append(spans, r.Spans())
+			}
+			if !slices.EqualFunc(tags, tt.tags, slices.Equal) {
+				t.Fatalf("expected tags %v, got %v", tt.tags, tags)
+			}
+			if !slices.Equal(texts, tt.texts) {
+				t.Fatalf("expected texts %q, got %q", tt.texts, texts)
+			}
+			if !slices.EqualFunc(spans, tt.spans, slices.Equal) {
+				t.Fatalf("expected spans %v, got %v", tt.spans, spans)
+			}
+		})
+	}
+
+	// Keywords are disabled by default
+	if results := scanSource(t, "a.go", "// TODO: Clean up", Options{}); len(results) != 0 {
+		t.Fatalf("expected no reminders without keywords, got %d", len(results))
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Attribute tests
+//
+// -----------------------------------------------------------------------------
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+var attributeTests = []struct {
+	name   string
+	source string
+	text   string
+	spans  []reminder.Span
+	attrs  reminder.Attributes
+}{
+	{
+		name:   "assignee",
+		source: "// @todo(alice) Clean up",
+		text:   "todo(alice) Clean up",
+		spans:  []reminder.Span{{Start: 0, End: 11}},
+		attrs:  reminder.Attributes{Assignee: "alice"},
+	},
+	{
+		name:   "due",
+		source: "// @todo @due(2026-12-01): Release",
+		text:   "todo due(2026-12-01) Release",
+		spans:  []reminder.Span{{Start: 0, End: 4}, {Start: 5, End: 20}},
+		attrs:  reminder.Attributes{Due: date("2026-12-01")},
+	},
+	{
+		name:   "priority",
+		source: "// @fix @p(1) Crash",
+		text:   "fix p(1) Crash",
+		spans:  []reminder.Span{{Start: 0, End: 3}, {Start: 4, End: 8}},
+		attrs:  reminder.Attributes{Priority: 1},
+	},
+	{
+		name:   "issue",
+		source: "// @bug @issue(PROJ-12)",
+		text:   "bug issue(PROJ-12)",
+		spans:  []reminder.Span{{Start: 0, End: 3}, {Start: 4, End: 18}},
+		attrs:  reminder.Attributes{Issue: "PROJ-12"},
+	},
+	{
+		name:   "classified",
+		source: "// @todo(bob, #12, 2026-01-02, p3) All",
+		text:   "todo(bob, #12, 2026-01-02, p3) All",
+		spans:  []reminder.Span{{Start: 0, End: 30}},
+		attrs: reminder.Attributes{
+			Assignee: "bob",
+			Due:      date("2026-01-02"),
+			Priority: 3,
+			Issue:    "#12",
+		},
+	},
+	{
+		name:   "colon quarter",
+		source: "// @todo @by:2026-Q4. Ship it",
+		text:   "todo by:2026-Q4. Ship it",
+		spans:  []reminder.Span{{Start: 0, End: 4}, {Start: 5, End: 15}},
+		attrs:  reminder.Attributes{Due: date("2026-12-31")},
+	},
+	{
+		name:   "colon comma",
+		source: "// Ship it @by:2026-Q4, then @todo clean up",
+		text:   "Ship it by:2026-Q4, then todo clean up",
+		spans:  []reminder.Span{{Start: 8, End: 18}, {Start: 25, End: 29}},
+		attrs:  reminder.Attributes{Due: date("2026-12-31")},
+	},
+	{
+		name:   "month",
+		source: "// @due(2026-02)",
+		text:   "due(2026-02)",
+		spans:  []reminder.Span{{Start: 0, End: 12}},
+		attrs:  reminder.Attributes{Due: date("2026-02-28")},
+	},
+	{
+		name:   "week",
+		source: "// @due:2026-W01",
+		text:   "due:2026-W01",
+		spans:  []reminder.Span{{Start: 0, End: 12}},
+		attrs:  reminder.Attributes{Due: date("2026-01-04")},
+	},
+	{
+		name:   "colon text",
+		source: "// @todo:later",
+		text:   "todo later",
+		spans:  []reminder.Span{{Start: 0, End: 4}},
+	},
+	{
+		name:   "bad date",
+		source: "// @due(soon)",
+		text:   "due(soon)",
+		spans:  []reminder.Span{{Start: 0, End: 9}},
+	},
+	{
+		name:   "unclosed",
+		source: "// @todo(alice Clean up",
+		text:   "todo (alice Clean up",
+		spans:  []reminder.Span{{Start: 0, End: 4}},
+	},
+}
+
+func TestAttributes(t *testing.T) {
+	for _, tt := range attributeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := scanSource(t, "a.go", tt.source, Options{})
+			if len(results) != 1 {
+				t.Fatalf("expected 1 reminder, got %d", len(results))
+			}
+
+			r := results[0]
+			if r.Text() != tt.text {
+				t.Fatalf("expected text %q, got %q", tt.text, r.Text())
+			}
+			if !slices.Equal(r.Spans(), tt.spans) {
+				t.Fatalf("expected spans %v, got %v", tt.spans, r.Spans())
+			}
+			if r.Attributes() != tt.attrs {
+				t.Fatalf("expected attributes %+v, got %+v", tt.attrs, r.Attributes())
+			}
+		})
+	}
+}
+
+func TestOverdue(t *testing.T) {
+	results := scanSource(t, "a.go", "// @due(2026-03-01)", Options{})
+	r := results[0]
+	if r.Overdue(date("2026-03-01").Add(23 * time.Hour)) {
+		t.Fatalf("expected reminder not to be overdue on its due date")
+	}
+	if !r.Overdue(date("2026-03-02")) {
+		t.Fatalf("expected reminder to be overdue after its due date")
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Hierarchical tag tests
+//
+// -----------------------------------------------------------------------------
+
+func TestHierarchicalTags(t *testing.T) {
+	source := "// @Bug/UI @later.perf: Slow rendering of @bug/ui. Ends with @todo/"
+	results := scanSource(t, "a.go", source, Options{})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 reminder, got %d", len(results))
+	}
+
+	r := results[0]
+	tags := []string{"bug/ui", "later/perf", "todo"}
+	if !slices.Equal(r.Tags(), tags) {
+		t.Fatalf("expected tags %v, got %v", tags, r.Tags())
+	}
+	text := "Bug/UI later.perf Slow rendering of. Ends with todo /"
+	if r.Text() != text {
+		t.Fatalf("expected text %q, got %q", text, r.Text())
+	}
+	spans := []reminder.Span{{Start: 0, End: 6}, {Start: 7, End: 17}, {Start: 47, End: 51}}
+	if !slices.Equal(r.Spans(), spans) {
+		t.Fatalf("expected spans %v, got %v", spans, r.Spans())
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Alias tests
+//
+// -----------------------------------------------------------------------------
+
+func TestAliases(t *testing.T) {
+	opts := Options{Aliases: reminder.Aliases{"fixme": "fix", "bugfix": "fix"}}
+	results := scanSource(t, "a.go", "// @FIXME @fix @BugFix/ui @bug Broken", opts)
+	if len(results) != 1 {
+		t.Fatalf("expected 1 reminder, got %d", len(results))
+	}
+
+	r := results[0]
+	tags := []string{"fix", "fix/ui", "bug"}
+	if !slices.Equal(r.Tags(), tags) {
+		t.Fatalf("expected tags %v, got %v", tags, r.Tags())
+	}
+	text := "FIXME BugFix/ui bug Broken"
+	if r.Text() != text {
+		t.Fatalf("expected text %q, got %q", text, r.Text())
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Fingerprint tests
+//
+// -----------------------------------------------------------------------------
+
+func TestFingerprints(t *testing.T) {
+	before := scanSource(t, "a.go", "// @todo Refactor\n// @todo Refactor\nx := 1\n// @fix Broken\n", Options{})
+	after := scanSource(t, "a.go", "package a\n\n// @todo  refactor\n\n// @todo Refactor\nx := 2\n// @fix Broken\n", Options{})
+	moved := scanSource(t, "b.go", "// @fix Broken\n", Options{})
+
+	if len(before) != 3 || len(after) != 3 {
+		t.Fatalf("expected 3 reminders, got %d and %d", len(before), len(after))
+	}
+	for i := range before {
+		if before[i].Fingerprint() != after[i].Fingerprint() {
+			t.Errorf("reminder %d: fingerprint changed when editing lines above", i)
+		}
+	}
+	if before[0].Fingerprint() == before[1].Fingerprint() {
+		t.Error("expected equal reminders to have distinct fingerprints")
+	}
+	if before[1].Occurrence() != 1 {
+		t.Errorf("expected occurrence 1, got %d", before[1].Occurrence())
+	}
+	if before[2].Fingerprint() == moved[0].Fingerprint() {
+		t.Error("expected reminders of different files to have distinct fingerprints")
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Column tests
+//
+// -----------------------------------------------------------------------------
+
+var columnTests = []struct {
+	name   string
+	file   string
+	source string
+	column int
+}{
+	{name: "line", file: "a.go", source: "// @todo x", column: 4},
+	{name: "trailing", file: "a.go", source: "x := 1 //  @todo x", column: 12},
+	{name: "second tag", file: "a.go", source: "// Fix later @todo", column: 14},
+	{name: "keyword", file: "a.go", source: "\t// TODO: x", column: 5},
+	{name: "block", file: "a.c", source: "/* @fix x */", column: 4},
+	{name: "block line", file: "a.c", source: "/*\n * @fix x\n */", column: 4},
+	{name: "unicode", file: "a.go", source: "s := \"å\" // @todo x", column: 13},
+	{name: "continued", file: "a.go", source: "// @todo x\n// y", column: 4},
+}
+
+func TestColumns(t *testing.T) {
+	for _, tt := range columnTests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := scanSource(t, tt.file, tt.source, Options{Keywords: DefaultKeywords})
+			if len(results) != 1 {
+				t.Fatalf("expected 1 reminder, got %d", len(results))
+			}
+			if col := results[0].Column(); col != tt.column {
+				t.Fatalf("expected column %d, got %d", tt.column, col)
+			}
+		})
+	}
+}
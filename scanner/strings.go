@@ -0,0 +1,158 @@
+package scanner
+
+import "unicode/utf8"
+
+// scanString skips past the string literal starting at the current
+// position, leaving the closing delimiter as the current rune.
+// It reports whether a string literal was found.
+func (s *Scanner) scanString() bool {
+	for i := range s.syn.quotes {
+		q := &s.syn.quotes[i]
+		if s.ch > 0xFF || s.ch != rune(q.open[0]) {
+			continue
+		}
+
+		switch q.kind {
+		case Quoted:
+			if q.doc && s.opts.DocComments && s.blank && s.match(q.open) {
+				s.scanDocString(q)
+				return true
+			}
+			if s.match(q.open) {
+				s.advance(len(q.open))
+				s.skipQuoted(q.close, q.escape, q.multiline)
+				return true
+			}
+		case Char:
+			if n := s.charLitLen(); n > 0 {
+				s.advance(n)
+				return true
+			}
+		case RustRaw:
+			if s.scanRustRaw() {
+				return true
+			}
+		case CppRaw:
+			if s.scanCppRaw() {
+				return true
+			}
+		case LongBracket:
+			p, _ := s.rd.Peek(64)
+			if close := longBracketAfter(p); close != nil {
+				s.advance(len(close))
+				s.skipQuoted(close, false, true)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// skipQuoted moves the scanner to the last rune of the closing
+// delimiter. Unless multiline, a newline also ends the string.
+func (s *Scanner) skipQuoted(close []byte, escape, multiline bool) {
+	for s.ch != eof {
+		switch {
+		case s.ch == '\n' && !multiline:
+			return
+		case escape && s.ch == '\\':
+			s.next()
+		case s.match(close):
+			s.advance(len(close) - 1)
+			return
+		}
+		s.next()
+	}
+}
+
+// charLitLen returns the number of runes following the opening
+// quote of a character literal, including the closing quote,
+// or 0 if the current quote doesn't start a character literal.
+func (s *Scanner) charLitLen() int {
+	p, _ := s.rd.Peek(12)
+	if len(p) < 2 {
+		return 0
+	}
+
+	if p[0] == '\\' {
+		for i := 2; i < len(p) && p[i] != '\n'; i++ {
+			if p[i] == '\'' {
+				return i + 1
+			}
+		}
+		return 0
+	}
+
+	_, size := utf8.DecodeRune(p)
+	if p[0] != '\'' && p[0] != '\n' && size < len(p) && p[size] == '\'' {
+		return 2
+	}
+	return 0
+}
+
+// scanRustRaw skips a raw string like r"..." or r#"..."#.
+func (s *Scanner) scanRustRaw() bool {
+	p, _ := s.rd.Peek(256)
+	n := 0
+	for n < len(p) && p[n] == '#' {
+		n++
+	}
+	if n >= len(p) || p[n] != '"' {
+		return false
+	}
+
+	close := make([]byte, n+1)
+	close[0] = '"'
+	for i := 1; i <= n; i++ {
+		close[i] = '#'
+	}
+	s.advance(n + 2)
+	s.skipQuoted(close, false, true)
+	return true
+}
+
+// scanCppRaw skips a raw string like R"delim(...)delim".
+func (s *Scanner) scanCppRaw() bool {
+	p, _ := s.rd.Peek(18)
+	if len(p) < 2 || p[0] != '"' {
+		return false
+	}
+
+	for i := 1; i < len(p); i++ {
+		switch p[i] {
+		case '(':
+			close := make([]byte, 0, i+1)
+			close = append(close, ')')
+			close = append(close, p[1:i]...)
+			close = append(close, '"')
+			s.advance(i + 2)
+			s.skipQuoted(close, false, true)
+			return true
+		case ')', '\\', ' ', '\t', '\n', '"':
+			return false
+		}
+	}
+	return false
+}
+
+// longBracketAfter returns the closing delimiter of the long bracket
+// like [[ or [==[ whose first `[` precedes p, or nil if p doesn't
+// continue a long bracket. The opening and closing delimiters have
+// the same length.
+func longBracketAfter(p []byte) []byte {
+	n := 0
+	for n < len(p) && p[n] == '=' {
+		n++
+	}
+	if n >= len(p) || p[n] != '[' {
+		return nil
+	}
+
+	close := make([]byte, n+2)
+	close[0] = ']'
+	for i := 1; i <= n; i++ {
+		close[i] = '='
+	}
+	close[n+1] = ']'
+	return close
+}
@@ -0,0 +1,123 @@
+package searcher
+
+import (
+	"path"
+	"strings"
+)
+
+// A glob is a compiled gitignore-style pattern, matched against
+// slash-separated paths relative to a base directory.
+//
+// A pattern without a slash, apart from a trailing one, matches the
+// name of a file or directory at any depth below the base directory.
+// Other patterns are anchored to the base directory. A `**` segment
+// matches any number of directories, and a trailing slash only
+// matches directories.
+type glob struct {
+	segs     []string
+	anchored bool
+	dirOnly  bool
+}
+
+func compileGlob(pattern string) glob {
+	var g glob
+
+	if strings.HasSuffix(pattern, "/") {
+		g.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if strings.Contains(pattern, "/") {
+		g.anchored = true
+		pattern = strings.TrimPrefix(pattern, "/")
+	}
+
+	g.segs = strings.Split(pattern, "/")
+	for i, seg := range g.segs {
+		// Character classes are negated with `!` in gitignore
+		g.segs[i] = strings.ReplaceAll(seg, "[!", "[^")
+	}
+	return g
+}
+
+// match reports whether the glob matches the relative path,
+// which names a directory if isDir.
+func (g *glob) match(rel string, isDir bool) bool {
+	if g.dirOnly && !isDir {
+		return false
+	}
+	if !g.anchored {
+		return matchSegment(g.segs[0], path.Base(rel))
+	}
+	return matchSegments(g.segs, strings.Split(rel, "/"))
+}
+
+func matchSegments(pat, name []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			// Collapse consecutive `**` segments
+			for len(pat) > 0 && pat[0] == "**" {
+				pat = pat[1:]
+			}
+			// A trailing `**` matches everything inside
+			if len(pat) == 0 {
+				return len(name) > 0
+			}
+			for i := range name {
+				if matchSegments(pat, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 || !matchSegment(pat[0], name[0]) {
+			return false
+		}
+		pat = pat[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+func matchSegment(pat, name string) bool {
+	ok, err := path.Match(pat, name)
+	return ok && err == nil
+}
+
+// matchPath reports whether the glob matches the relative path or
+// any of its parent directories.
+func (g *glob) matchPath(rel string, isDir bool) bool {
+	for {
+		if g.match(rel, isDir) {
+			return true
+		}
+		i := strings.LastIndexByte(rel, '/')
+		if i < 0 {
+			return false
+		}
+		rel = rel[:i]
+		isDir = true
+	}
+}
+
+// matchPrefix reports whether the glob may match a path inside the
+// directory rel. Only anchored globs naming the top directory of rel
+// may match inside it.
+func (g *glob) matchPrefix(rel string) bool {
+	if !g.anchored {
+		return false
+	}
+	name := strings.Split(rel, "/")
+	for i, seg := range g.segs {
+		if seg == "**" {
+			return i > 0
+		}
+		if i == len(name) {
+			return true
+		}
+		if !matchSegment(seg, name[i]) {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package searcher
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// DefaultIgnoreFiles are the names of the ignore files honored by
+// default, in increasing order of precedence.
+var DefaultIgnoreFiles = []string{".gitignore", ".ignore", ".remindersignore"}
+
+// An ignoreRule is a single pattern from an ignore file.
+type ignoreRule struct {
+	glob
+	negate bool
+}
+
+// An ignorer decides which paths are excluded by the ignore files
+// found while walking a file hierarchy. Ignore files use gitignore
+// semantics, with the rules of deeper directories and later rules
+// taking precedence.
+type ignorer struct {
+	fsys  fs.FS
+	names []string
+	rules map[string][]ignoreRule // By directory
+}
+
+func newIgnorer(fsys fs.FS, names []string) *ignorer {
+	ig := &ignorer{
+		fsys:  fsys,
+		names: names,
+		rules: make(map[string][]ignoreRule),
+	}
+	if len(names) > 0 {
+		// Repository specific exclusions
+		ig.rules["."] = ig.read(".git/info/exclude")
+	}
+	return ig
+}
+
+// load reads the ignore files of a directory.
+func (ig *ignorer) load(dir string) {
+	for _, name := range ig.names {
+		if rules := ig.read(path.Join(dir, name)); len(rules) > 0 {
+			ig.rules[dir] = append(ig.rules[dir], rules...)
+		}
+	}
+}
+
+func (ig *ignorer) read(file string) []ignoreRule {
+	data, err := fs.ReadFile(ig.fsys, file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Failed to read %q: %v\n", file, err)
+		}
+		return nil
+	}
+	return parseIgnore(data)
+}
+
+// ignored reports whether the path, which names a directory if isDir,
+// is excluded by the loaded ignore files.
+func (ig *ignorer) ignored(p string, isDir bool) bool {
+	if len(ig.rules) == 0 {
+		return false
+	}
+
+	ignored := false
+	dir := "."
+	rel := p
+	for {
+		for _, r := range ig.rules[dir] {
+			if r.match(rel, isDir) {
+				ignored = !r.negate
+			}
+		}
+
+		seg, rest, ok := strings.Cut(rel, "/")
+		if !ok {
+			return ignored
+		}
+		dir = path.Join(dir, seg)
+		rel = rest
+	}
+}
+
+// parseIgnore parses the rules of an ignore file.
+func parseIgnore(data []byte) []ignoreRule {
+	var rules []ignoreRule
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+
+		// Trailing spaces are ignored unless escaped
+		if trimmed := strings.TrimRight(line, " "); strings.HasSuffix(trimmed, "\\") && trimmed != line {
+			line = trimmed + " "
+		} else {
+			line = trimmed
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		var r ignoreRule
+		if line[0] == '!' {
+			r.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if line == "" || line == "/" {
+			continue
+		}
+
+		r.glob = compileGlob(line)
+		rules = append(rules, r)
+	}
+	return rules
+}
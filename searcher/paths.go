@@ -0,0 +1,259 @@
+package searcher
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MHmorgan/reminders/git"
+)
+
+// WalkPaths calls fn for each file which should be scanned among the
+// given files and directories of the operating system, with the reason
+// it is scanned. Directories are walked like Walk, with the rules
+// matched relative to each directory, and the paths of their files
+// joined with the directory. Files given explicitly are always scanned.
+//
+// A file found through several of the paths is only reported once.
+func (s *Searcher) WalkPaths(paths []string, fn func(path, reason string)) {
+	s.walkPaths(paths, func(f found) { fn(f.path, f.reason) })
+}
+
+func (s *Searcher) walkPaths(paths []string, fn func(found)) {
+	w := s.newPathWalker(fn)
+	for _, p := range paths {
+		w.walk(p, true)
+	}
+}
+
+// WalkList is like WalkPaths, for a list of paths separated by sep,
+// like the output of `git diff --name-only`. Unlike with WalkPaths,
+// listed files are only scanned if they are included by their
+// extension or the rules, which are matched against the listed paths.
+// Ignore files are not honored for listed files.
+func (s *Searcher) WalkList(r io.Reader, sep byte, fn func(path, reason string)) error {
+	return s.walkList(r, sep, func(f found) { fn(f.path, f.reason) })
+}
+
+func (s *Searcher) walkList(r io.Reader, sep byte, fn func(found)) error {
+	w := s.newPathWalker(fn)
+	rd := bufio.NewReader(r)
+	for {
+		line, err := rd.ReadString(sep)
+		p := strings.TrimSuffix(strings.TrimSuffix(line, string(sep)), "\r")
+		if p != "" {
+			w.walk(p, false)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// SearchPaths is like Search, for the files given by WalkPaths.
+func (s *Searcher) SearchPaths(paths []string) <-chan Result {
+	return s.open(func(fn func(found)) {
+		s.walkPaths(paths, fn)
+	})
+}
+
+// SearchList is like Search, for the files given by WalkList.
+func (s *Searcher) SearchList(r io.Reader, sep byte) <-chan Result {
+	return s.open(func(fn func(found)) {
+		if err := s.walkList(r, sep, fn); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read path list: %v\n", err)
+		}
+	})
+}
+
+// open sends the opened files found by walk.
+func (s *Searcher) open(walk func(fn func(found))) <-chan Result {
+	out := make(chan Result, 1)
+
+	go func() {
+		defer close(out)
+		walk(func(f found) {
+			if file, err := f.fsys.Open(f.name); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to open %q: %v\n", f.path, err)
+			} else {
+				out <- Result{f.path, file}
+			}
+		})
+	}()
+
+	return out
+}
+
+// found is a file found by a pathWalker, which is named name
+// in the file system fsys.
+type found struct {
+	path   string
+	reason string
+	fsys   fs.FS
+	name   string
+}
+
+// A pathWalker walks files and directories of the operating system,
+// or of a git revision, reporting each file once.
+type pathWalker struct {
+	srch  *Searcher
+	seen  map[string]bool
+	fn    func(found)
+	trees map[string]fs.FS // By working tree
+}
+
+func (s *Searcher) newPathWalker(fn func(found)) *pathWalker {
+	return &pathWalker{s, make(map[string]bool), fn, make(map[string]fs.FS)}
+}
+
+func (w *pathWalker) report(f found) {
+	key, err := filepath.Abs(f.path)
+	if err != nil {
+		key = f.path
+	}
+	if w.seen[key] {
+		return
+	}
+	w.seen[key] = true
+	w.fn(f)
+}
+
+// walk reports the file p, or the files of the directory p. The file
+// is always reported if explicit, otherwise like a file found by Walk.
+func (w *pathWalker) walk(p string, explicit bool) {
+	p = filepath.Clean(p)
+	fsys, name, info, err := w.locate(p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Search error: %v\n", err)
+		return
+	}
+
+	switch {
+	case info.IsDir():
+		w.walkDir(p, fsys, name)
+	case explicit:
+		w.report(found{p, "explicit path", fsys, name})
+	case info.Mode().IsRegular():
+		rel := filepath.ToSlash(p)
+		if dec := w.srch.decide(rel, false, w.srch.byExtension(rel)); dec.scan {
+			w.report(found{p, dec.reason, fsys, name})
+		}
+	}
+}
+
+// locate returns the file system containing the path, and its name
+// and info in the file system.
+func (w *pathWalker) locate(p string) (fs.FS, string, fs.FileInfo, error) {
+	if w.srch.rev != "" {
+		tree, name, err := w.revision(p)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		info, err := fs.Stat(tree, name)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("%s at %s: %w", p, w.srch.rev, fs.ErrNotExist)
+		}
+		return tree, name, info, nil
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if info.IsDir() {
+		return os.DirFS(p), ".", info, nil
+	}
+	return os.DirFS(filepath.Dir(p)), filepath.Base(p), info, nil
+}
+
+// revision returns the files of the searched revision of the git
+// repository containing the path, and the name of the path in it.
+func (w *pathWalker) revision(p string) (fs.FS, string, error) {
+	repo, err := git.Find(p)
+	if err != nil {
+		return nil, "", err
+	}
+	name, err := repo.Rel(p)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tree, ok := w.trees[repo.WorkTree]
+	if !ok {
+		h, err := repo.Resolve(w.srch.rev)
+		if err != nil {
+			return nil, "", err
+		}
+		if tree, err = repo.TreeFS(h); err != nil {
+			return nil, "", err
+		}
+		w.trees[repo.WorkTree] = tree
+	}
+	return tree, name, nil
+}
+
+// walkDir reports the files of the directory p, which is named name
+// in fsys.
+func (w *pathWalker) walkDir(p string, fsys fs.FS, name string) {
+	walk := func(fsys fs.FS, suffix string) {
+		w.srch.Walk(fsys, func(rel, reason string) {
+			w.report(found{
+				path:   filepath.Join(p, filepath.FromSlash(rel)),
+				reason: reason + suffix,
+				fsys:   fsys,
+				name:   rel,
+			})
+		})
+	}
+
+	if name != "." {
+		sub, err := fs.Sub(fsys, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Search error: %v\n", err)
+			return
+		}
+		fsys = sub
+	}
+
+	if !w.srch.git || w.srch.rev != "" {
+		walk(fsys, "")
+		return
+	}
+
+	tracked, err := trackedFS(p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Search error: %v\n", err)
+		walk(fsys, "")
+		return
+	}
+	walk(tracked, ", tracked")
+	if w.srch.untracked {
+		// Tracked files are already reported
+		walk(fsys, ", untracked")
+	}
+}
+
+// trackedFS returns a file system of the files in the directory p
+// which are tracked by the git repository containing it.
+func trackedFS(p string) (fs.FS, error) {
+	repo, err := git.Find(p)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Index()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := repo.Rel(p)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Sub(idx.FS(os.DirFS(repo.WorkTree)), dir)
+}
@@ -0,0 +1,52 @@
+package searcher
+
+import "fmt"
+
+// A Rule includes or excludes the files matching a gitignore-style
+// glob pattern, like `docs/**/*.md`, `**/*_test.go` or `internal/gen/`.
+// A rule matching a directory applies to all files inside it.
+type Rule struct {
+	Pattern string `json:"pattern"`
+	Exclude bool   `json:"exclude,omitempty"`
+}
+
+func (r Rule) String() string {
+	if r.Exclude {
+		return fmt.Sprintf("exclude %q", r.Pattern)
+	}
+	return fmt.Sprintf("include %q", r.Pattern)
+}
+
+type rule struct {
+	glob
+	Rule
+}
+
+// A decision is whether a path should be scanned, and why.
+type decision struct {
+	scan   bool
+	reason string
+	rule   int // Index of the deciding rule, or -1
+}
+
+// decide applies the rules to a path, starting with the default
+// decision. The last matching rule wins.
+func (s *Searcher) decide(p string, isDir bool, d decision) decision {
+	for i, r := range s.rules {
+		if r.matchPath(p, isDir) {
+			d = decision{!r.Exclude, r.String(), i}
+		}
+	}
+	return d
+}
+
+// mayInclude reports whether any rule after the i-th one may include
+// a file inside the directory.
+func (s *Searcher) mayInclude(dir string, i int) bool {
+	for _, r := range s.rules[i+1:] {
+		if !r.Exclude && r.matchPrefix(dir) {
+			return true
+		}
+	}
+	return false
+}
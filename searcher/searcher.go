@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -11,13 +12,57 @@ import (
 type StringSet map[string]bool
 
 // Searcher finds the source code files which should be scanned for reminders.
+//
+// By default, files are scanned if their extension is included, or
+// if they have no extension. Directories in the exclude set, hidden
+// directories and paths ignored by ignore files are skipped.
+// Rules added with AddRules take precedence over the defaults.
 type Searcher struct {
-	include StringSet
-	exclude StringSet
+	include     StringSet
+	exclude     StringSet
+	ignoreFiles []string
+	rules       []rule
+	git         bool
+	untracked   bool
+	rev         string
 }
 
 func New(include, exclude StringSet) Searcher {
-	return Searcher{include, exclude}
+	return Searcher{include: include, exclude: exclude, ignoreFiles: DefaultIgnoreFiles}
+}
+
+// SetIgnoreFiles replaces the names of the ignore files honored
+// while searching, like .gitignore. Later names take precedence.
+func (s *Searcher) SetIgnoreFiles(names []string) {
+	s.ignoreFiles = names
+}
+
+// SetGit makes WalkPaths and SearchPaths list the files of directories
+// in git repositories from the index, which is faster than walking large
+// working trees. Untracked files which are not ignored are also listed
+// if untracked is set.
+func (s *Searcher) SetGit(untracked bool) {
+	s.git = true
+	s.untracked = untracked
+}
+
+// SetRevision makes WalkPaths and SearchPaths search the files of
+// a revision, like a tag, of the git repositories containing the paths,
+// instead of their working trees.
+func (s *Searcher) SetRevision(rev string) {
+	s.rev = rev
+}
+
+// AddRules appends include and exclude rules, which are matched
+// against paths relative to the searched root. Later rules take
+// precedence.
+//
+// A directory excluded by default or by a rule is only searched if
+// a later include rule names it, like `.github/**/*.yml`.
+func (s *Searcher) AddRules(rules ...Rule) {
+	for _, r := range rules {
+		s.rules = append(s.rules, rule{compileGlob(r.Pattern), r})
+	}
 }
 
 func (s *Searcher) Search(fsys fs.FS) <-chan Result {
@@ -25,30 +70,55 @@ func (s *Searcher) Search(fsys fs.FS) <-chan Result {
 
 	go func() {
 		defer close(out)
-		s.search(out, fsys)
+		s.Walk(fsys, func(path, reason string) {
+			if f, err := fsys.Open(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to open %q: %v\n", path, err)
+			} else {
+				out <- Result{path, f}
+			}
+		})
 	}()
 
 	return out
 }
 
-func (s *Searcher) search(out chan<- Result, fsys fs.FS) {
-	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+// Walk calls fn for each file which should be scanned, with the
+// reason it is scanned, without opening it.
+func (s *Searcher) Walk(fsys fs.FS, fn func(path, reason string)) {
+	ign := newIgnorer(fsys, s.ignoreFiles)
+	// Directories which are only searched for the include rules
+	excluded := make(map[string]bool)
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if p == "." {
+			ign.load(p)
+			return nil
+		}
+		inExcluded := excluded[path.Dir(p)]
 
 		if d.IsDir() {
 			name := d.Name()
-			if path == "." {
-				return nil
-			}
-			if s.exclude[name] {
-				return fs.SkipDir
-			}
+			dflt := decision{scan: true, rule: -1}
+			switch {
+			case inExcluded, s.exclude[name]:
+				dflt.scan = false
 			// Skip hidden directories
-			if strings.HasPrefix(name, ".") {
-				return fs.SkipDir
+			case strings.HasPrefix(name, "."):
+				dflt.scan = false
+			case ign.ignored(p, true):
+				dflt.scan = false
+			}
+
+			if dec := s.decide(p, true, dflt); !dec.scan {
+				if !s.mayInclude(p, dec.rule) {
+					return fs.SkipDir
+				}
+				excluded[p] = true
 			}
+			ign.load(p)
 			return nil
 		}
 
@@ -56,15 +126,13 @@ func (s *Searcher) search(out chan<- Result, fsys fs.FS) {
 			return nil
 		}
 
-		ext := filepath.Ext(path)
-		if !s.include[ext] && ext != "" {
-			return nil
+		dflt := decision{rule: -1}
+		if !inExcluded && !ign.ignored(p, false) {
+			dflt = s.byExtension(p)
 		}
 
-		if f, err := fsys.Open(path); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to open %q: %v", path, err)
-		} else {
-			out <- Result{path, f}
+		if dec := s.decide(p, false, dflt); dec.scan {
+			fn(p, dec.reason)
 		}
 		return nil
 	})
@@ -73,3 +141,14 @@ func (s *Searcher) search(out chan<- Result, fsys fs.FS) {
 		fmt.Fprintf(os.Stderr, "Search error: %v\n", err)
 	}
 }
+
+// byExtension returns the default decision for a file, by its extension.
+func (s *Searcher) byExtension(p string) decision {
+	switch ext := filepath.Ext(p); {
+	case ext == "":
+		return decision{true, "no extension", -1}
+	case s.include[ext]:
+		return decision{true, fmt.Sprintf("extension %s", ext), -1}
+	}
+	return decision{rule: -1}
+}
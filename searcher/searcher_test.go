@@ -0,0 +1,246 @@
+package searcher
+
+import (
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func searchPaths(t *testing.T, srch Searcher, fsys fs.FS) []string {
+	t.Helper()
+
+	var paths []string
+	for res := range srch.Search(fsys) {
+		paths = append(paths, res.Path)
+		res.File.Close()
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// -----------------------------------------------------------------------------
+//
+// Glob tests
+//
+// -----------------------------------------------------------------------------
+
+var globTests = []struct {
+	pattern string
+	path    string
+	isDir   bool
+	match   bool
+}{
+	{pattern: "*.go", path: "a.go", match: true},
+	{pattern: "*.go", path: "x/y/a.go", match: true},
+	{pattern: "/*.go", path: "x/a.go", match: false},
+	{pattern: "/*.go", path: "a.go", match: true},
+	{pattern: "x/*.go", path: "x/a.go", match: true},
+	{pattern: "x/*.go", path: "y/x/a.go", match: false},
+	{pattern: "build/", path: "build", isDir: true, match: true},
+	{pattern: "build/", path: "build", match: false},
+	{pattern: "**/gen", path: "a/b/gen", isDir: true, match: true},
+	{pattern: "**/gen", path: "gen", isDir: true, match: true},
+	{pattern: "a/**/b", path: "a/b", match: true},
+	{pattern: "a/**/b", path: "a/x/y/b", match: true},
+	{pattern: "a/**", path: "a/x/y", match: true},
+	{pattern: "a/**", path: "a", isDir: true, match: false},
+	{pattern: "file[!0-9].txt", path: "filex.txt", match: true},
+	{pattern: "file[!0-9].txt", path: "file1.txt", match: false},
+	{pattern: "docs/**/*.md", path: "docs/a/b/c.md", match: true},
+	{pattern: "**/*_test.go", path: "a/b_test.go", match: true},
+}
+
+func TestGlob(t *testing.T) {
+	for _, tt := range globTests {
+		g := compileGlob(tt.pattern)
+		if got := g.match(tt.path, tt.isDir); got != tt.match {
+			t.Errorf("glob %q on %q (dir %v): expected %v, got %v", tt.pattern, tt.path, tt.isDir, tt.match, got)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Ignore file tests
+//
+// -----------------------------------------------------------------------------
+
+var ignoreFS = fstest.MapFS{
+	".gitignore":                {Data: []byte("# Generated\n*.gen.go\n/vendor/\nnode_modules\n!keep.gen.go\n")},
+	".ignore":                   {Data: []byte("legacy/\n")},
+	".remindersignore":          {Data: []byte("docs/**/*.md\n")},
+	".git/info/exclude":         {Data: []byte("local.go\n")},
+	"main.go":                   {},
+	"local.go":                  {},
+	"api.gen.go":                {},
+	"keep.gen.go":               {},
+	"vendor/dep.go":             {},
+	"web/node_modules/x.js":     {},
+	"web/app.js":                {},
+	"legacy/old.go":             {},
+	"docs/guide/intro.md":       {},
+	"docs/README.md":            {},
+	"pkg/.gitignore":            {Data: []byte("/secret.go\n!api.gen.go\n")},
+	"pkg/secret.go":             {},
+	"pkg/api.gen.go":            {},
+	"pkg/sub/secret.go":         {},
+	"pkg/sub/vendor/vendor.go":  {},
+	"pkg/escaped/#hash.go":      {},
+	"pkg/escaped/.gitignore":    {Data: []byte(`\#hash.go` + "\n")},
+	"pkg/escaped/notignored.go": {},
+}
+
+func TestIgnoreFiles(t *testing.T) {
+	include := StringSet{".go": true, ".js": true, ".md": true}
+	srch := New(include, StringSet{})
+
+	expect := []string{
+		"keep.gen.go",
+		"main.go",
+		"pkg/api.gen.go",
+		"pkg/escaped/notignored.go",
+		"pkg/sub/secret.go",
+		"pkg/sub/vendor/vendor.go",
+		"web/app.js",
+	}
+	if paths := searchPaths(t, srch, ignoreFS); !slices.Equal(paths, expect) {
+		t.Fatalf("expected paths\n%q\ngot\n%q", expect, paths)
+	}
+
+	srch.SetIgnoreFiles(nil)
+	if paths := searchPaths(t, srch, ignoreFS); len(paths) != 16 {
+		t.Fatalf("expected all 16 files without ignore files, got %d: %q", len(paths), paths)
+	}
+}
+
+// -----------------------------------------------------------------------------
+//
+// Rule tests
+//
+// -----------------------------------------------------------------------------
+
+var rulesFS = fstest.MapFS{
+	"main.go":                       {},
+	"main_test.go":                  {},
+	"Makefile":                      {},
+	"docs/guide/intro.md":           {},
+	"docs/notes.txt":                {},
+	"internal/gen/api.go":           {},
+	"internal/gen/keep.go":          {},
+	"internal/lib.go":               {},
+	"build/out.go":                  {},
+	".github/workflows/ci.yml":      {},
+	".github/CODEOWNERS.md":         {},
+	"vendor/dep/dep.go":             {},
+	"vendor/dep/dep_test.go":        {},
+	"vendor/dep/docs/dep/readme.md": {},
+}
+
+func TestRules(t *testing.T) {
+	include := StringSet{".go": true}
+	srch := New(include, StringSet{"build": true})
+	srch.AddRules(
+		Rule{Pattern: "docs/**/*.md"},
+		Rule{Pattern: "**/*_test.go", Exclude: true},
+		Rule{Pattern: "internal/gen/", Exclude: true},
+		Rule{Pattern: "internal/gen/keep.go"},
+		Rule{Pattern: ".github/**/*.yml"},
+		Rule{Pattern: "vendor/", Exclude: true},
+		Rule{Pattern: "Makefile", Exclude: true},
+	)
+
+	expect := map[string]string{
+		".github/workflows/ci.yml": `include ".github/**/*.yml"`,
+		"docs/guide/intro.md":      `include "docs/**/*.md"`,
+		"internal/gen/keep.go":     `include "internal/gen/keep.go"`,
+		"internal/lib.go":          "extension .go",
+		"main.go":                  "extension .go",
+	}
+	got := make(map[string]string)
+	srch.Walk(rulesFS, func(path, reason string) {
+		got[path] = reason
+	})
+	if !maps.Equal(got, expect) {
+		t.Fatalf("expected files\n%q\ngot\n%q", expect, got)
+	}
+}
+
+func TestWalkPaths(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "a.txt", "src/b.go", "src/c.go"} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srch := New(StringSet{".go": true}, StringSet{})
+	src := filepath.Join(root, "src")
+	paths := []string{
+		src,
+		root,
+		filepath.Join(root, "a.txt"),
+		filepath.Join(src, "c.go"),
+	}
+
+	var got []string
+	srch.WalkPaths(paths, func(path, reason string) {
+		got = append(got, path)
+	})
+	expect := []string{
+		filepath.Join(src, "b.go"),
+		filepath.Join(src, "c.go"),
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "a.txt"),
+	}
+	if !slices.Equal(got, expect) {
+		t.Fatalf("expected paths\n%q\ngot\n%q", expect, got)
+	}
+}
+
+func TestWalkList(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a.go", "a_test.go", "a.txt", "src/b.go"} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srch := New(StringSet{".go": true}, StringSet{})
+	srch.AddRules(Rule{Pattern: "*_test.go", Exclude: true})
+
+	list := strings.Join([]string{
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "a_test.go"),
+		filepath.Join(root, "a.txt"),
+		filepath.Join(root, "src"),
+		filepath.Join(root, "a.go"),
+	}, "\x00")
+
+	var got []string
+	err := srch.WalkList(strings.NewReader(list), 0, func(path, reason string) {
+		got = append(got, path)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		filepath.Join(root, "a.go"),
+		filepath.Join(root, "src", "b.go"),
+	}
+	if !slices.Equal(got, expect) {
+		t.Fatalf("expected paths\n%q\ngot\n%q", expect, got)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/scanner"
+	"github.com/MHmorgan/reminders/tio"
+)
+
+// templateReminder is a reminder as seen by output templates.
+type templateReminder struct {
+	reminder.Reminder
+	Overdue bool
+}
+
+// Message returns the text of the reminder without its leading tags.
+func (r *templateReminder) Message() string {
+	return messageOf(r.Reminder)
+}
+
+// templateFile is the reminders of a file as seen by output templates.
+type templateFile struct {
+	Path      string
+	Reminders []*templateReminder
+}
+
+// The functions available to output templates, in addition to the
+// built-in ones.
+var templateFuncs = template.FuncMap{
+	"bold":      style(tio.Bold),
+	"dim":       style(tio.Dim),
+	"italic":    style(tio.Italic),
+	"underline": style(tio.Underscore),
+	"red":       style(tio.FgRed),
+	"green":     style(tio.FgGreen),
+	"yellow":    style(tio.FgYellow),
+	"blue":      style(tio.FgBlue),
+	"magenta":   style(tio.FgMagenta),
+	"cyan":      style(tio.FgCyan),
+	"rel":       relPath,
+	"join":      func(sep string, list []string) string { return strings.Join(list, sep) },
+	"tags":      joinTags,
+	"date":      func(t time.Time) string { return t.Format(time.DateOnly) },
+}
+
+// Join tags, each with an at sign, separated by spaces.
+func joinTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "@" + strings.Join(tags, " @")
+}
+
+// Return the path relative to the working directory, or the path
+// itself if it can't be made relative, like a relative path.
+func relPath(p string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return p
+	}
+	if rel, err := filepath.Rel(cwd, p); err == nil {
+		return rel
+	}
+	return p
+}
+
+// Return a template function wrapping its argument in a tio style.
+func style(code string) func(any) string {
+	return func(v any) string {
+		return code + fmt.Sprint(v) + tio.Reset
+	}
+}
+
+// Parse an output template, given inline or as the name of a file.
+func parseTemplate(text string) (*template.Template, error) {
+	name := "template"
+	if fi, err := os.Stat(text); err == nil && fi.Mode().IsRegular() {
+		data, err := os.ReadFile(text)
+		if err != nil {
+			return nil, err
+		}
+		name = filepath.Base(text)
+		text = string(data)
+	}
+	return template.New(name).Funcs(templateFuncs).Parse(text)
+}
+
+// Print the received reminders with a user-defined template, using
+// the given filter. The template is executed for each reminder.
+// If it defines a "file" template, that template is executed for
+// each file with printed reminders, before the reminders, and if
+// it defines a "summary" template, that template is executed last,
+// with the number of scanned files and lines. Returns the number of
+// printed reminders which are overdue.
+func printTemplate(
+	w io.Writer,
+	f filter,
+	scanRes <-chan scanner.Result,
+	tmpl *template.Template,
+) (int, error) {
+	bw := bufio.NewWriter(w)
+	fileTmpl := tmpl.Lookup("file")
+
+	// Reminders are executed per file, once the file is complete
+	var file templateFile
+	flush := func() error {
+		if len(file.Reminders) == 0 {
+			return nil
+		}
+		if fileTmpl != nil {
+			if err := fileTmpl.Execute(bw, file); err != nil {
+				return err
+			}
+		}
+		for _, tr := range file.Reminders {
+			if err := tmpl.Execute(bw, tr); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var t tally
+	now := time.Now()
+	for r := range f.matching(scanRes, &t) {
+		if r.File() != file.Path {
+			if err := flush(); err != nil {
+				return t.overdue, err
+			}
+			file = templateFile{Path: r.File()}
+		}
+		file.Reminders = append(file.Reminders, &templateReminder{r, r.Overdue(now)})
+	}
+	if err := flush(); err != nil {
+		return t.overdue, err
+	}
+
+	if st := tmpl.Lookup("summary"); st != nil {
+		if err := st.Execute(bw, summary{t.files, scanner.ScannedLines.Load()}); err != nil {
+			return t.overdue, err
+		}
+	}
+	return t.overdue, bw.Flush()
+}
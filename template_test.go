@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MHmorgan/reminders/reminder"
+	"github.com/MHmorgan/reminders/tio"
+)
+
+const testTemplate = `{{define "file"}}== {{.Path}} ({{len .Reminders}})
+{{end}}{{define "summary"}}{{.Files}} files
+{{end}}{{.Line}} {{tags .Tags}} [{{join "," .Tags}}] {{.Message}}{{if .Overdue}} {{bold "late"}}{{end}}{{with .Attributes.Due}}{{if not .IsZero}} due {{date .}}{{end}}{{end}}
+`
+
+func TestTemplate(t *testing.T) {
+	due := time.Date(2020, time.January, 2, 0, 0, 0, 0, time.Local)
+	a1 := reminder.New("a.go", 1, "todo p(1) Refactor", []string{"todo", "p"}, []reminder.Span{{Start: 0, End: 4}, {Start: 5, End: 9}})
+	a2 := reminder.New("a.go", 4, "fix Late", []string{"fix"}, []reminder.Span{{Start: 0, End: 3}})
+	a2.SetAttributes(reminder.Attributes{Due: due})
+	b1 := reminder.New("b.go", 2, "todo Other", []string{"todo"}, []reminder.Span{{Start: 0, End: 4}})
+
+	file := filepath.Join(t.TempDir(), "out.tmpl")
+	if err := os.WriteFile(file, []byte(testTemplate), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	expect := "== a.go (2)\n" +
+		"1 @todo @p [todo,p] Refactor\n" +
+		"4 @fix [fix] Late " + tio.Bold + "late" + tio.Reset + " due 2020-01-02\n" +
+		"== b.go (1)\n" +
+		"2 @todo [todo] Other\n" +
+		"2 files\n"
+
+	// Templates are given inline or as a file
+	for _, text := range []string{testTemplate, file} {
+		tmpl, err := parseTemplate(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		n, err := printTemplate(&buf, filter{}, scanResults(a1, a2, b1), tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expect {
+			t.Fatalf("expected output\n%q\ngot\n%q", expect, buf.String())
+		}
+		if n != 1 {
+			t.Fatalf("expected 1 overdue reminder, got %d", n)
+		}
+	}
+}
+
+func TestTemplateError(t *testing.T) {
+	if _, err := parseTemplate("{{.Line"); err == nil {
+		t.Error("expected a parse error")
+	}
+
+	tmpl, err := parseTemplate("{{.Nope}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := reminder.New("a.go", 1, "todo", []string{"todo"}, nil)
+	if _, err := printTemplate(new(bytes.Buffer), filter{}, scanResults(r), tmpl); err == nil {
+		t.Error("expected an execution error")
+	}
+}
+
+func TestRelPath(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Absolute paths, like those of absolute roots and -files-from
+	if rel := relPath(filepath.Join(cwd, "src", "a.go")); rel != filepath.Join("src", "a.go") {
+		t.Errorf("expected src/a.go, got %q", rel)
+	}
+	// Relative paths are already relative to the working directory
+	if rel := relPath(filepath.Join("src", "a.go")); rel != filepath.Join("src", "a.go") {
+		t.Errorf("expected src/a.go, got %q", rel)
+	}
+
+	tmpl, err := parseTemplate("{{rel .File}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := reminder.New(filepath.Join(cwd, "a.go"), 1, "todo", []string{"todo"}, nil)
+	var buf bytes.Buffer
+	if _, err := printTemplate(&buf, filter{}, scanResults(r), tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "a.go" {
+		t.Errorf("expected a.go, got %q", buf.String())
+	}
+}